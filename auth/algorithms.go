@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// AllowAlgorithms returns a key function which only accepts tokens signed with one of the given algorithms,
+// eg. "RS256" or "EdDSA", and only when the key of the parser is of the matching type.
+// This prevents a token from choosing how it is verified, such as an HMAC token verified with a public RSA key as its secret.
+func AllowAlgorithms(algs ...string) PublicKeyFunc {
+	allowed := make(map[string]bool, len(algs))
+	for _, alg := range algs {
+		allowed[alg] = true
+	}
+	return func(pk crypto.PublicKey) jwt.Keyfunc {
+		return func(token *jwt.Token) (interface{}, error) {
+			alg := token.Method.Alg()
+			if !allowed[alg] {
+				return nil, ErrAlgorithmNotAllowed
+			}
+			if !keyMatchesAlgorithm(pk, alg) {
+				return nil, ErrAlgorithmKeyMismatch
+			}
+			return pk, nil
+		}
+	}
+}
+
+// Algorithms returns the signing algorithms which can be used with a key, either private or public.
+// Keys of an unsupported type have no algorithms.
+func Algorithms(key interface{}) []string {
+	switch key := key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		return []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	case *ecdsa.PrivateKey:
+		return ecdsaAlgorithms(key.Curve)
+	case *ecdsa.PublicKey:
+		return ecdsaAlgorithms(key.Curve)
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return []string{SigningMethodEdDSA.Alg()}
+	case []byte:
+		return []string{"HS256", "HS384", "HS512"}
+	default:
+		return nil
+	}
+}
+
+func ecdsaAlgorithms(curve elliptic.Curve) []string {
+	switch curve {
+	case elliptic.P256():
+		return []string{"ES256"}
+	case elliptic.P384():
+		return []string{"ES384"}
+	case elliptic.P521():
+		return []string{"ES512"}
+	default:
+		return nil
+	}
+}
+
+// keyMatchesAlgorithm reports whether a key can be used to verify tokens signed with an algorithm.
+func keyMatchesAlgorithm(key interface{}, alg string) bool {
+	for _, a := range Algorithms(key) {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// signingMethod infers the default signing method for a private key.
+func signingMethod(private crypto.PrivateKey) jwt.SigningMethod {
+	algs := Algorithms(private)
+	if len(algs) == 0 {
+		return nil
+	}
+	return jwt.GetSigningMethod(algs[0])
+}
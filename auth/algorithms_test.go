@@ -0,0 +1,144 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/auth/authmock"
+	"github.com/LUSHDigital/core/test"
+)
+
+func ExampleAllowAlgorithms() {
+	public := []byte(`... public key ...`)
+	parser, err := auth.NewParserFromPEM(public, auth.AllowAlgorithms("RS256", "ES256"))
+	if err != nil {
+		return
+	}
+	var claims jwt.StandardClaims
+	if err := parser.Parse(`... jwt ...`, &claims); err != nil {
+		return
+	}
+}
+
+func TestIssuer_Issue_algorithms(t *testing.T) {
+	rsaPrivate, rsaPublic := authmock.MustNewRSAKeyPair()
+	edPrivate, edPublic := authmock.MustNewEdDSAKeyPair()
+	secret := authmock.MustNewHMACSecret()
+	cases := []struct {
+		name    string
+		private interface{}
+		public  interface{}
+		method  jwt.SigningMethod
+		alg     string
+	}{
+		{name: "RS256", private: rsaPrivate, public: rsaPublic, alg: "RS256"},
+		{name: "PS512", private: rsaPrivate, public: rsaPublic, method: jwt.SigningMethodPS512, alg: "PS512"},
+		{name: "ES256", private: mustECDSA(elliptic.P256()), alg: "ES256"},
+		{name: "ES384", private: mustECDSA(elliptic.P384()), alg: "ES384"},
+		{name: "ES512", private: mustECDSA(elliptic.P521()), alg: "ES512"},
+		{name: "EdDSA", private: edPrivate, public: edPublic, alg: "EdDSA"},
+		{name: "HS256", private: secret, public: secret, alg: "HS256"},
+		{name: "HS512", private: secret, public: secret, method: jwt.SigningMethodHS512, alg: "HS512"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			public := c.public
+			if key, ok := c.private.(*ecdsa.PrivateKey); ok {
+				public = &key.PublicKey
+			}
+			raw, err := auth.NewIssuer(c.private, c.method).Issue(&claims)
+			test.Equals(t, nil, err)
+			var parsed auth.Claims
+			token, err := jwt.ParseWithClaims(raw, &parsed, func(*jwt.Token) (interface{}, error) { return public, nil })
+			test.Equals(t, nil, err)
+			test.Equals(t, c.alg, token.Header["alg"])
+			test.Equals(t, nil, auth.NewParser(public, nil).Parse(raw, &parsed))
+			test.Equals(t, claims.Consumer.ID, parsed.Consumer.ID)
+		})
+	}
+}
+
+func TestAllowAlgorithms(t *testing.T) {
+	rsaPrivate, rsaPublic := authmock.MustNewRSAKeyPair()
+	ecPrivate := mustECDSA(elliptic.P256())
+	rsaToken, err := auth.NewIssuer(rsaPrivate, nil).Issue(&claims)
+	test.Equals(t, nil, err)
+	ecToken, err := auth.NewIssuer(ecPrivate, nil).Issue(&claims)
+	test.Equals(t, nil, err)
+
+	// An HMAC token signed with the public RSA key as its secret must not verify against that key.
+	der, err := jwt.ParseRSAPublicKeyFromPEM(mustReadFile(t, "testdata/public.rsa.key"))
+	test.Equals(t, nil, err)
+	confused, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims).SignedString(mustReadFile(t, "testdata/public.rsa.key"))
+	test.Equals(t, nil, err)
+
+	cases := []struct {
+		name   string
+		public interface{}
+		algs   []string
+		raw    string
+		err    error
+	}{
+		{name: "allowed", public: rsaPublic, algs: []string{"RS256"}, raw: rsaToken},
+		{name: "not allowed", public: rsaPublic, algs: []string{"RS512"}, raw: rsaToken, err: auth.ErrAlgorithmNotAllowed},
+		{name: "key mismatch", public: rsaPublic, algs: []string{"RS256", "ES256"}, raw: ecToken, err: auth.ErrAlgorithmKeyMismatch},
+		{name: "hmac with public key", public: der, algs: []string{"RS256", "HS256"}, raw: confused, err: auth.ErrAlgorithmKeyMismatch},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var parsed auth.Claims
+			err := auth.NewParser(c.public, auth.AllowAlgorithms(c.algs...)).Parse(c.raw, &parsed)
+			test.Equals(t, c.err, err)
+		})
+	}
+}
+
+func TestNewParser_defaultAlgorithms(t *testing.T) {
+	private, public := authmock.MustNewRSAKeyPair()
+	raw, err := auth.NewIssuer(private, jwt.SigningMethodRS384).Issue(&claims)
+	test.Equals(t, nil, err)
+	var parsed auth.Claims
+	test.Equals(t, nil, auth.NewParser(public, nil).Parse(raw, &parsed))
+
+	_, edPublic := authmock.MustNewEdDSAKeyPair()
+	err = auth.NewParser(edPublic, nil).Parse(raw, &parsed)
+	test.Equals(t, auth.ErrAlgorithmNotAllowed, err)
+}
+
+func TestPrivateKeyFromPEM_ed25519(t *testing.T) {
+	private, err := auth.PrivateKeyFromPEM(mustReadFile(t, "testdata/private.ed25519.key"))
+	test.Equals(t, nil, err)
+	public, err := auth.PublicKeyFromPEM(mustReadFile(t, "testdata/public.ed25519.key"))
+	test.Equals(t, nil, err)
+	test.Equals(t, private.(ed25519.PrivateKey).Public(), public)
+}
+
+func TestPrivateKeyFromPEMWithPassword_ecdsa(t *testing.T) {
+	private, err := auth.PrivateKeyFromPEMWithPassword(mustReadFile(t, "testdata/encrypted.private.ecdsa.key"), "secret")
+	test.Equals(t, nil, err)
+	test.Equals(t, []string{"ES384"}, auth.Algorithms(private))
+}
+
+func mustECDSA(curve elliptic.Curve) *ecdsa.PrivateKey {
+	private, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return private
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	t.Helper()
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -12,20 +12,11 @@ import (
 	"github.com/LUSHDigital/core/auth/authmock"
 )
 
-type Consumer struct {
-	ID string
-}
-
-type Claims struct {
-	jwt.StandardClaims
-	Consumer
-}
-
 var (
 	issuer, invalidIssuer *auth.Issuer
 	parser, invalidParser *auth.Parser
 
-	claims, expiredClaims, futureClaims, invalidClaims Claims
+	claims, expiredClaims, futureClaims, invalidClaims auth.Claims
 
 	now       time.Time
 	then      time.Time
@@ -38,10 +29,9 @@ func TestMain(m *testing.M) {
 	then = now.Add(-(76 * time.Hour))
 	at = now.Add(76 * time.Hour)
 	validTime = time.Hour
-	jwt.TimeFunc = func() time.Time { return now }
 	issuer, parser = authmock.MustNewRSAIsserAndParser()
 	invalidIssuer, invalidParser = authmock.MustNewRSAIsserAndParser()
-	claims = Claims{
+	claims = auth.Claims{
 		StandardClaims: jwt.StandardClaims{
 			Id:        uuid.Must(uuid.NewV4()).String(),
 			Issuer:    "Auth Test",
@@ -49,8 +39,10 @@ func TestMain(m *testing.M) {
 			IssuedAt:  then.Unix(),
 			NotBefore: then.Unix(),
 		},
-		Consumer: Consumer{
-			ID: uuid.Must(uuid.NewV4()).String(),
+		Consumer: auth.Consumer{
+			ID:     uuid.Must(uuid.NewV4()).String(),
+			Grants: []string{"orders.read"},
+			Roles:  []string{"customer"},
 		},
 	}
 	os.Exit(m.Run())
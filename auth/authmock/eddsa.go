@@ -0,0 +1,65 @@
+package authmock
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// EdDSAKeyFunc represents the keyfunc for the mock parser.
+func EdDSAKeyFunc(pk crypto.PublicKey) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if token.Method != auth.SigningMethodEdDSA {
+			return pk, fmt.Errorf("unknown algorithm: %v", token.Header["alg"])
+		}
+		return pk, nil
+	}
+}
+
+// NewEdDSAKeyPair will create a key pair.
+func NewEdDSAKeyPair() (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return private, public, nil
+}
+
+// MustNewEdDSAKeyPair will create a key pair and will panic on failure.
+func MustNewEdDSAKeyPair() (ed25519.PrivateKey, ed25519.PublicKey) {
+	private, public, err := NewEdDSAKeyPair()
+	if err != nil {
+		panic(err)
+	}
+	return private, public
+}
+
+// NewEdDSAIssuerAndParser creates a new issuer with a random key pair.
+func NewEdDSAIssuerAndParser() (*auth.Issuer, *auth.Parser, error) {
+	private, public, err := NewEdDSAKeyPair()
+	if err != nil {
+		return nil, nil, err
+	}
+	issuer, parser := NewEdDSAIssuerAndParserFromKeyPair(private, public)
+	return issuer, parser, nil
+}
+
+// MustNewEdDSAIssuerAndParser creates a new issuer and parser with a random key pair and will panic on failure.
+func MustNewEdDSAIssuerAndParser() (*auth.Issuer, *auth.Parser) {
+	issuer, parser, err := NewEdDSAIssuerAndParser()
+	if err != nil {
+		panic(err)
+	}
+	return issuer, parser
+}
+
+// NewEdDSAIssuerAndParserFromKeyPair creates a new issuer and parser from an ed25519 key pair.
+func NewEdDSAIssuerAndParserFromKeyPair(private ed25519.PrivateKey, public ed25519.PublicKey) (*auth.Issuer, *auth.Parser) {
+	issuer := auth.NewIssuer(private, auth.SigningMethodEdDSA)
+	parser := auth.NewParser(public, EdDSAKeyFunc)
+	return issuer, parser
+}
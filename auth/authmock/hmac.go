@@ -0,0 +1,68 @@
+package authmock
+
+import (
+	"crypto"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	secretSize = 64
+)
+
+// HMACKeyFunc represents the keyfunc for the mock parser.
+func HMACKeyFunc(pk crypto.PublicKey) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return pk, fmt.Errorf("unknown algorithm: %v", token.Header["alg"])
+		}
+		return pk, nil
+	}
+}
+
+// NewHMACSecret will create a random secret.
+func NewHMACSecret() ([]byte, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// MustNewHMACSecret will create a random secret and will panic on failure.
+func MustNewHMACSecret() []byte {
+	secret, err := NewHMACSecret()
+	if err != nil {
+		panic(err)
+	}
+	return secret
+}
+
+// NewHMACIssuerAndParser creates a new issuer and parser with a random secret.
+func NewHMACIssuerAndParser() (*auth.Issuer, *auth.Parser, error) {
+	secret, err := NewHMACSecret()
+	if err != nil {
+		return nil, nil, err
+	}
+	issuer, parser := NewHMACIssuerAndParserFromSecret(secret)
+	return issuer, parser, nil
+}
+
+// MustNewHMACIssuerAndParser creates a new issuer and parser with a random secret and will panic on failure.
+func MustNewHMACIssuerAndParser() (*auth.Issuer, *auth.Parser) {
+	issuer, parser, err := NewHMACIssuerAndParser()
+	if err != nil {
+		panic(err)
+	}
+	return issuer, parser
+}
+
+// NewHMACIssuerAndParserFromSecret creates a new issuer and parser sharing a secret.
+func NewHMACIssuerAndParserFromSecret(secret []byte) (*auth.Issuer, *auth.Parser) {
+	issuer := auth.NewIssuer(secret, jwt.SigningMethodHS256)
+	parser := auth.NewParser(secret, HMACKeyFunc)
+	return issuer, parser
+}
@@ -0,0 +1,61 @@
+package authmock
+
+import (
+	"crypto"
+
+	"github.com/LUSHDigital/core/auth"
+)
+
+// NewRSAEncryptedIssuerAndParser creates a new issuer and parser of encrypted tokens,
+// signing with a random EdDSA key pair and encrypting with a random RSA key pair.
+func NewRSAEncryptedIssuerAndParser() (*auth.EncryptedIssuer, *auth.Parser, error) {
+	private, public, err := NewRSAKeyPair()
+	if err != nil {
+		return nil, nil, err
+	}
+	return newEncryptedIssuerAndParser(private, public)
+}
+
+// MustNewRSAEncryptedIssuerAndParser creates a new issuer and parser of encrypted tokens with random RSA key pairs and will panic on failure.
+func MustNewRSAEncryptedIssuerAndParser() (*auth.EncryptedIssuer, *auth.Parser) {
+	issuer, parser, err := NewRSAEncryptedIssuerAndParser()
+	if err != nil {
+		panic(err)
+	}
+	return issuer, parser
+}
+
+// NewECDSAEncryptedIssuerAndParser creates a new issuer and parser of encrypted tokens,
+// signing with a random EdDSA key pair and encrypting with a random ECDSA key pair.
+func NewECDSAEncryptedIssuerAndParser() (*auth.EncryptedIssuer, *auth.Parser, error) {
+	private, public, err := NewECDSAKeyPair()
+	if err != nil {
+		return nil, nil, err
+	}
+	return newEncryptedIssuerAndParser(private, public)
+}
+
+// MustNewECDSAEncryptedIssuerAndParser creates a new issuer and parser of encrypted tokens with random ECDSA key pairs and will panic on failure.
+func MustNewECDSAEncryptedIssuerAndParser() (*auth.EncryptedIssuer, *auth.Parser) {
+	issuer, parser, err := NewECDSAEncryptedIssuerAndParser()
+	if err != nil {
+		panic(err)
+	}
+	return issuer, parser
+}
+
+// NewEncryptedIssuerAndParserFromKeyPair creates a new issuer and parser of encrypted tokens from a signing issuer and parser,
+// and an RSA or ECDSA key pair to encrypt the tokens with.
+func NewEncryptedIssuerAndParserFromKeyPair(issuer *auth.Issuer, parser *auth.Parser, private crypto.PrivateKey, public crypto.PublicKey) (*auth.EncryptedIssuer, *auth.Parser) {
+	parser.DecryptionKey = private
+	return auth.NewEncryptedIssuer(issuer, public), parser
+}
+
+func newEncryptedIssuerAndParser(private crypto.PrivateKey, public crypto.PublicKey) (*auth.EncryptedIssuer, *auth.Parser, error) {
+	issuer, parser, err := NewEdDSAIssuerAndParser()
+	if err != nil {
+		return nil, nil, err
+	}
+	encrypted, parser := NewEncryptedIssuerAndParserFromKeyPair(issuer, parser, private, public)
+	return encrypted, parser, nil
+}
@@ -0,0 +1,48 @@
+package authmock
+
+import (
+	"crypto"
+	"io/ioutil"
+
+	"github.com/LUSHDigital/core/auth"
+)
+
+const (
+	fileMode = 0600
+)
+
+// WriteKeyPair will write the PEM encodings of a private key and its public key to files, eg. as fixtures for tests.
+func WriteKeyPair(private crypto.PrivateKey, privatePath, publicPath string) error {
+	privatePEM, err := auth.PrivateKeyToPEM(private)
+	if err != nil {
+		return err
+	}
+	return writeKeyPair(private, privatePEM, privatePath, publicPath)
+}
+
+// MustWriteKeyPair will write the PEM encodings of a private key and its public key to files and will panic on failure.
+func MustWriteKeyPair(private crypto.PrivateKey, privatePath, publicPath string) {
+	if err := WriteKeyPair(private, privatePath, publicPath); err != nil {
+		panic(err)
+	}
+}
+
+// WriteEncryptedKeyPair will write the PEM encodings of a private key encrypted with a password and its public key to files.
+func WriteEncryptedKeyPair(private crypto.PrivateKey, password, privatePath, publicPath string) error {
+	privatePEM, err := auth.PrivateKeyToPEMWithPassword(private, password)
+	if err != nil {
+		return err
+	}
+	return writeKeyPair(private, privatePEM, privatePath, publicPath)
+}
+
+func writeKeyPair(private crypto.PrivateKey, privatePEM []byte, privatePath, publicPath string) error {
+	publicPEM, err := auth.PublicKeyToPEM(private)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(privatePath, privatePEM, fileMode); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(publicPath, publicPEM, fileMode)
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Claims represents the standard claims of a token issued to an api consumer.
+type Claims struct {
+	jwt.StandardClaims
+	Consumer `json:"consumer"`
+}
+
+// Consumer represents the api consumer a token was issued to.
+type Consumer struct {
+	// ID is the identifier of the consumer.
+	ID string `json:"id"`
+	// Grants are the permissions or scopes granted to the consumer, eg. "orders.read".
+	Grants []string `json:"grants,omitempty"`
+	// Roles are the roles the consumer has, eg. "admin".
+	Roles []string `json:"roles,omitempty"`
+	// Tenant is the tenant the consumer belongs to, if any.
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// HasGrant reports whether the consumer has been granted a permission.
+func (c Consumer) HasGrant(grant string) bool {
+	return contains(c.Grants, grant)
+}
+
+// HasAnyGrant reports whether the consumer has been granted any of the permissions.
+func (c Consumer) HasAnyGrant(grants ...string) bool {
+	for _, grant := range grants {
+		if c.HasGrant(grant) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAllGrants reports whether the consumer has been granted every one of the permissions.
+func (c Consumer) HasAllGrants(grants ...string) bool {
+	for _, grant := range grants {
+		if !c.HasGrant(grant) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasRole reports whether the consumer has a role.
+func (c Consumer) HasRole(role string) bool {
+	return contains(c.Roles, role)
+}
+
+// HasAnyRole reports whether the consumer has any of the roles.
+func (c Consumer) HasAnyRole(roles ...string) bool {
+	for _, role := range roles {
+		if c.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
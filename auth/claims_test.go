@@ -0,0 +1,65 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/test"
+)
+
+func ExampleClaimsFromContext() {
+	claims, ok := auth.ClaimsFromContext(context.Background())
+	if !ok || !claims.HasGrant("orders.read") {
+		return
+	}
+}
+
+func TestClaims_JSON(t *testing.T) {
+	c := auth.Claims{
+		StandardClaims: jwt.StandardClaims{Subject: "consumer"},
+		Consumer: auth.Consumer{
+			ID:     "1234",
+			Grants: []string{"orders.read"},
+			Roles:  []string{"admin"},
+			Tenant: "uk",
+		},
+	}
+	b, err := json.Marshal(c)
+	test.Equals(t, nil, err)
+	test.Equals(t, `{"sub":"consumer","consumer":{"id":"1234","grants":["orders.read"],"roles":["admin"],"tenant":"uk"}}`, string(b))
+
+	raw, err := issuer.Issue(&c)
+	test.Equals(t, nil, err)
+	var parsed auth.Claims
+	test.Equals(t, nil, parser.Parse(raw, &parsed))
+	test.Equals(t, c, parsed)
+}
+
+func TestConsumer_grantsAndRoles(t *testing.T) {
+	c := auth.Consumer{
+		Grants: []string{"orders.read", "orders.write"},
+		Roles:  []string{"customer"},
+	}
+	test.Equals(t, true, c.HasGrant("orders.read"))
+	test.Equals(t, false, c.HasGrant("orders.delete"))
+	test.Equals(t, true, c.HasAnyGrant("orders.delete", "orders.write"))
+	test.Equals(t, false, c.HasAnyGrant())
+	test.Equals(t, true, c.HasAllGrants("orders.read", "orders.write"))
+	test.Equals(t, false, c.HasAllGrants("orders.read", "orders.delete"))
+	test.Equals(t, true, c.HasRole("customer"))
+	test.Equals(t, true, c.HasAnyRole("admin", "customer"))
+	test.Equals(t, false, c.HasAnyRole("admin"))
+}
+
+func TestClaimsFromContext(t *testing.T) {
+	_, ok := auth.ClaimsFromContext(context.Background())
+	test.Equals(t, false, ok)
+
+	c, ok := auth.ClaimsFromContext(auth.ContextWithClaims(context.Background(), claims))
+	test.Equals(t, true, ok)
+	test.Equals(t, claims, c)
+}
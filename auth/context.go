@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"context"
+)
+
+type key int
+
+const (
+	claimsKey key = iota
+)
+
+// ContextWithClaims takes a context and the claims of a token and returns
+// a new context with the claims embedded.
+func ContextWithClaims(parent context.Context, claims Claims) context.Context {
+	return context.WithValue(parent, claimsKey, claims)
+}
+
+// ClaimsFromContext extracts the claims from the supplied context, reporting whether there were any.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(Claims)
+	return claims, ok
+}
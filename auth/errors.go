@@ -7,8 +7,64 @@ var (
 	ErrKeyMustBePEMEncoded = errors.New("invalid key: must be PEM encoded PKCS1 or PKCS8 private key")
 	// ErrNotRSAPrivateKey happens when the key is not a valid RSA private key.
 	ErrNotRSAPrivateKey = errors.New("invalid key: must be a valid RSA private key")
-	// ErrNotPrivateKey happens when the key is neither an RSA or ECDSA private key.
-	ErrNotPrivateKey = errors.New("invalid key: must be either an RSA or ECDSA private key")
-	// ErrNotPublicKey happens when the key is neither an RSA or ECDSA public key.
-	ErrNotPublicKey = errors.New("invalid key: must be either an RSA or ECDSA public key")
+	// ErrNotPrivateKey happens when the key is not an RSA, ECDSA or Ed25519 private key.
+	ErrNotPrivateKey = errors.New("invalid key: must be an RSA, ECDSA or Ed25519 private key")
+	// ErrNotPublicKey happens when the key is not an RSA, ECDSA or Ed25519 public key.
+	ErrNotPublicKey = errors.New("invalid key: must be an RSA, ECDSA or Ed25519 public key")
+	// ErrKeyEncrypted happens when an encrypted private key is read without a password.
+	ErrKeyEncrypted = errors.New("invalid key: encrypted, a password is required")
+	// ErrUnsupportedKeyEncryption happens when a private key is encrypted with a scheme which is not supported,
+	// such as an encrypted OpenSSH key.
+	ErrUnsupportedKeyEncryption = errors.New("invalid key: unsupported encryption")
+	// ErrInvalidKeyEncryption happens when the parameters a private key is encrypted with are out of range,
+	// such as a PBKDF2 iteration count too large to derive the key in a reasonable time.
+	ErrInvalidKeyEncryption = errors.New("invalid key: encryption parameters out of range")
+	// ErrNotOpenSSHKey happens when a key in the OpenSSH format cannot be decoded.
+	ErrNotOpenSSHKey = errors.New("invalid key: must be a valid OpenSSH key")
+	// ErrAlgorithmNotAllowed happens when a token is signed with an algorithm the parser does not allow.
+	ErrAlgorithmNotAllowed = errors.New("invalid token: signing algorithm is not allowed")
+	// ErrAlgorithmKeyMismatch happens when a token is signed with an algorithm which cannot be used with the key of the parser.
+	ErrAlgorithmKeyMismatch = errors.New("invalid token: signing algorithm does not match the key")
+	// ErrInvalidJWK happens when a JSON web key is not a valid RSA, ECDSA or Ed25519 public key.
+	ErrInvalidJWK = errors.New("invalid key: must be a valid RSA, EC or Ed25519 JSON web key")
+	// ErrMissingKeyID happens when a token has no key ID but is parsed using a key set.
+	ErrMissingKeyID = errors.New("invalid token: missing key id")
+	// ErrUnknownKeyID happens when there is no key with the key ID of a token.
+	ErrUnknownKeyID = errors.New("invalid token: unknown key id")
+	// ErrTokenMalformed happens when a token cannot be decoded.
+	ErrTokenMalformed = errors.New("invalid token: malformed")
+	// ErrSignatureInvalid happens when the signature of a token does not match its key.
+	ErrSignatureInvalid = errors.New("invalid token: signature is invalid")
+	// ErrTokenExpired happens when a token is used after it expires.
+	ErrTokenExpired = errors.New("invalid token: expired")
+	// ErrTokenNotValidYet happens when a token is used before it becomes valid.
+	ErrTokenNotValidYet = errors.New("invalid token: not valid yet")
+	// ErrTokenUsedBeforeIssued happens when a token is used before the time it was issued.
+	ErrTokenUsedBeforeIssued = errors.New("invalid token: used before issued")
+	// ErrTokenTooOld happens when a token was issued longer ago than the maximum age allowed.
+	ErrTokenTooOld = errors.New("invalid token: issued too long ago")
+	// ErrWrongIssuer happens when a token was issued by an issuer which is not accepted.
+	ErrWrongIssuer = errors.New("invalid token: wrong issuer")
+	// ErrWrongAudience happens when a token is not intended for the required audience.
+	ErrWrongAudience = errors.New("invalid token: wrong audience")
+	// ErrMissingClaim happens when a token does not have a required claim.
+	ErrMissingClaim = errors.New("invalid token: missing claim")
+	// ErrNoSigningKey happens when issuing a token before an issuer has a key to sign it with.
+	ErrNoSigningKey = errors.New("cannot issue token: no signing key")
+	// ErrTokenRevoked happens when a token is used after it has been revoked.
+	ErrTokenRevoked = errors.New("invalid token: revoked")
+	// ErrNotRefreshToken happens when a token other than a refresh token is used to refresh.
+	ErrNotRefreshToken = errors.New("invalid token: not a refresh token")
+	// ErrRefreshTokenReused happens when a refresh token is used more than once, revoking every token in its family.
+	ErrRefreshTokenReused = errors.New("invalid token: refresh token reused")
+	// ErrRefreshTokenNotAccepted happens when a refresh token is parsed as any other token, eg. used as an access token.
+	ErrRefreshTokenNotAccepted = errors.New("invalid token: refresh token not accepted")
+	// ErrNotEncryptionKey happens when a key other than an RSA or ECDSA key is used to encrypt or decrypt a token.
+	ErrNotEncryptionKey = errors.New("invalid key: must be an RSA or ECDSA key to encrypt tokens")
+	// ErrTokenEncrypted happens when an encrypted token is parsed by a parser without a decryption key.
+	ErrTokenEncrypted = errors.New("invalid token: encrypted but no decryption key")
+	// ErrTokenNotEncrypted happens when a token which is not encrypted is parsed by a parser with a decryption key.
+	ErrTokenNotEncrypted = errors.New("invalid token: not encrypted")
+	// ErrDecryptionFailed happens when an encrypted token cannot be decrypted with the key, or has been tampered with.
+	ErrDecryptionFailed = errors.New("invalid token: cannot decrypt")
 )
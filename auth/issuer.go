@@ -2,8 +2,6 @@ package auth
 
 import (
 	"crypto"
-	"crypto/ecdsa"
-	"crypto/rsa"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -13,6 +11,7 @@ import (
 type Issuer struct {
 	method  jwt.SigningMethod
 	private crypto.PrivateKey
+	kid     string
 	name    string
 	valid   time.Duration
 }
@@ -38,14 +37,11 @@ func NewIssuerFromPEMWithPassword(key []byte, password string, method jwt.Signin
 }
 
 // NewIssuer creates a new issuer.
+// The private key can be an RSA, ECDSA or Ed25519 private key, or a []byte secret for HMAC.
+// When no signing method is given, it is inferred from the key: RS256, ES256/384/512 by curve, EdDSA or HS256.
 func NewIssuer(private crypto.PrivateKey, method jwt.SigningMethod) *Issuer {
 	if method == nil {
-		switch private.(type) {
-		case *rsa.PrivateKey:
-			method = jwt.SigningMethodRS256
-		case *ecdsa.PrivateKey:
-			method = jwt.SigningMethodES256
-		}
+		method = signingMethod(private)
 	}
 	return &Issuer{
 		method:  method,
@@ -53,8 +49,23 @@ func NewIssuer(private crypto.PrivateKey, method jwt.SigningMethod) *Issuer {
 	}
 }
 
+// NewIssuerWithKeyID creates a new issuer which stamps the key ID in the "kid" header of every token it issues.
+func NewIssuerWithKeyID(kid string, private crypto.PrivateKey, method jwt.SigningMethod) *Issuer {
+	issuer := NewIssuer(private, method)
+	issuer.kid = kid
+	return issuer
+}
+
+// KeyID returns the key ID stamped on every token issued, if any.
+func (i *Issuer) KeyID() string {
+	return i.kid
+}
+
 // Issue will sign a JWT and return its string representation.
 func (i *Issuer) Issue(claims jwt.Claims) (string, error) {
 	token := jwt.NewWithClaims(i.method, claims)
+	if i.kid != "" {
+		token.Header["kid"] = i.kid
+	}
 	return token.SignedString(i.private)
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/LUSHDigital/core/auth"
 	"github.com/LUSHDigital/core/test"
 	"github.com/dgrijalva/jwt-go"
 )
@@ -35,7 +36,7 @@ func TestIssuer_Issue(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	var parsed Claims
+	var parsed auth.Claims
 	err = parser.Parse(raw, &parsed)
 	if err != nil {
 		t.Error(err)
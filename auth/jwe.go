@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// EncryptionRSAOAEP is the key management algorithm used to encrypt tokens for RSA keys.
+	EncryptionRSAOAEP = "RSA-OAEP"
+	// EncryptionRSAOAEP256 is a key management algorithm accepted when decrypting tokens with RSA keys.
+	EncryptionRSAOAEP256 = "RSA-OAEP-256"
+	// EncryptionECDHES is the key management algorithm used to encrypt tokens for ECDSA keys.
+	EncryptionECDHES = "ECDH-ES"
+	// EncryptionA256GCM is the content encryption algorithm used to encrypt tokens.
+	EncryptionA256GCM = "A256GCM"
+
+	cekSize   = 32
+	nonceSize = 12
+	tagSize   = 16
+)
+
+// jweHeader represents the protected header of an encrypted token.
+type jweHeader struct {
+	Algorithm   string `json:"alg"`
+	Encryption  string `json:"enc"`
+	ContentType string `json:"cty,omitempty"`
+	KeyID       string `json:"kid,omitempty"`
+	Ephemeral   *JWK   `json:"epk,omitempty"`
+}
+
+// NewEncryptedIssuer returns an issuer which signs tokens with the issuer and then encrypts them for the recipient,
+// with RSA-OAEP for an RSA public key or ECDH-ES for an ECDSA public key, and A256GCM.
+func NewEncryptedIssuer(issuer TokenIssuer, recipient crypto.PublicKey) *EncryptedIssuer {
+	return &EncryptedIssuer{
+		issuer:    issuer,
+		recipient: recipient,
+	}
+}
+
+// NewEncryptedIssuerFromPEM will take the public key PEM of the recipient and derive the public key from it.
+func NewEncryptedIssuerFromPEM(issuer TokenIssuer, key []byte) (*EncryptedIssuer, error) {
+	recipient, err := PublicKeyFromPEM(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptedIssuer(issuer, recipient), nil
+}
+
+// EncryptedIssuer represents an issuer of signed tokens nested in an encrypted token (JWE),
+// so the claims cannot be read by anyone but the recipient.
+type EncryptedIssuer struct {
+	issuer    TokenIssuer
+	recipient crypto.PublicKey
+}
+
+// Issue will sign a JWT, encrypt it and return the compact serialisation of the encrypted token.
+func (i *EncryptedIssuer) Issue(claims jwt.Claims) (string, error) {
+	signed, err := i.issuer.Issue(claims)
+	if err != nil {
+		return "", err
+	}
+	return Encrypt(signed, i.recipient)
+}
+
+// Encrypt encrypts a signed token for the recipient, returning the compact serialisation of a nested JWE.
+// The key is encrypted with RSA-OAEP for an RSA public key or agreed with ECDH-ES for an ECDSA public key,
+// and the token itself is encrypted with A256GCM.
+func Encrypt(signed string, recipient crypto.PublicKey) (string, error) {
+	header := jweHeader{
+		Encryption:  EncryptionA256GCM,
+		ContentType: "JWT",
+	}
+	var cek, encryptedKey []byte
+	switch key := publicKey(recipient).(type) {
+	case *rsa.PublicKey:
+		header.Algorithm = EncryptionRSAOAEP
+		cek = make([]byte, cekSize)
+		if _, err := io.ReadFull(rand.Reader, cek); err != nil {
+			return "", err
+		}
+		var err error
+		if encryptedKey, err = rsa.EncryptOAEP(sha1.New(), rand.Reader, key, cek, nil); err != nil {
+			return "", err
+		}
+	case *ecdsa.PublicKey:
+		header.Algorithm = EncryptionECDHES
+		ephemeral, err := ecdsa.GenerateKey(key.Curve, rand.Reader)
+		if err != nil {
+			return "", err
+		}
+		epk, err := NewJWK("", &ephemeral.PublicKey)
+		if err != nil {
+			return "", err
+		}
+		epk.Use, epk.Algorithm = "", ""
+		header.Ephemeral = &epk
+		cek = agreeKey(ephemeral, key, header.Encryption)
+	default:
+		return "", ErrNotEncryptionKey
+	}
+
+	protected, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	aad := encodeBase64(protected)
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nil, nonce, []byte(signed), []byte(aad))
+	ciphertext, tag := sealed[:len(sealed)-tagSize], sealed[len(sealed)-tagSize:]
+	return strings.Join([]string{
+		aad,
+		encodeBase64(encryptedKey),
+		encodeBase64(nonce),
+		encodeBase64(ciphertext),
+		encodeBase64(tag),
+	}, "."), nil
+}
+
+// Decrypt decrypts the compact serialisation of a JWE with the private key of the recipient, returning the nested token.
+// The nested token is not verified, which is left to the parser.
+func Decrypt(encrypted string, private crypto.PrivateKey) (string, error) {
+	parts := strings.Split(encrypted, ".")
+	if len(parts) != 5 {
+		return "", ErrTokenMalformed
+	}
+	var decoded [5][]byte
+	for i, part := range parts {
+		var err error
+		if decoded[i], err = decodeBase64(part); err != nil {
+			return "", ErrTokenMalformed
+		}
+	}
+	protected, encryptedKey, nonce, ciphertext, tag := decoded[0], decoded[1], decoded[2], decoded[3], decoded[4]
+	var header jweHeader
+	if err := json.Unmarshal(protected, &header); err != nil {
+		return "", ErrTokenMalformed
+	}
+	if header.Encryption != EncryptionA256GCM {
+		return "", ErrAlgorithmNotAllowed
+	}
+	if len(nonce) != nonceSize || len(tag) != tagSize {
+		return "", ErrTokenMalformed
+	}
+
+	var cek []byte
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		var h hash.Hash
+		switch header.Algorithm {
+		case EncryptionRSAOAEP:
+			h = sha1.New()
+		case EncryptionRSAOAEP256:
+			h = sha256.New()
+		default:
+			return "", ErrAlgorithmKeyMismatch
+		}
+		// A random key is used when decrypting the key fails, to not reveal whether it was the key or the content that failed.
+		cek = make([]byte, cekSize)
+		if _, err := io.ReadFull(rand.Reader, cek); err != nil {
+			return "", err
+		}
+		if decrypted, err := rsa.DecryptOAEP(h, nil, key, encryptedKey, nil); err == nil && len(decrypted) == cekSize {
+			subtle.ConstantTimeCopy(1, cek, decrypted)
+		}
+	case *ecdsa.PrivateKey:
+		if header.Algorithm != EncryptionECDHES {
+			return "", ErrAlgorithmKeyMismatch
+		}
+		if header.Ephemeral == nil || len(encryptedKey) != 0 {
+			return "", ErrTokenMalformed
+		}
+		epk, err := header.Ephemeral.PublicKey()
+		if err != nil {
+			return "", ErrTokenMalformed
+		}
+		ephemeral, ok := epk.(*ecdsa.PublicKey)
+		if !ok || ephemeral.Curve != key.Curve {
+			return "", ErrAlgorithmKeyMismatch
+		}
+		cek = agreeKey(key, ephemeral, header.Encryption)
+	default:
+		return "", ErrNotEncryptionKey
+	}
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return "", err
+	}
+	sealed := append(append([]byte{}, ciphertext...), tag...)
+	signed, err := gcm.Open(nil, nonce, sealed, []byte(parts[0]))
+	if err != nil {
+		return "", ErrDecryptionFailed
+	}
+	return string(signed), nil
+}
+
+// isEncrypted reports whether a token is in the compact serialisation of a JWE rather than a JWS.
+func isEncrypted(raw string) bool {
+	return strings.Count(raw, ".") == 4
+}
+
+// newGCM returns the authenticated cipher used to encrypt the content of a token.
+func newGCM(cek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// agreeKey derives the content encryption key from an ECDH-ES key agreement, using the Concat KDF (RFC 7518, section 4.6).
+func agreeKey(private *ecdsa.PrivateKey, public *ecdsa.PublicKey, enc string) []byte {
+	x, _ := private.Curve.ScalarMult(public.X, public.Y, private.D.Bytes())
+	size := (private.Curve.Params().BitSize + 7) / 8
+	z := padBytes(x.Bytes(), size)
+
+	// The other info consists of the algorithm, empty party info and the key length in bits.
+	var info []byte
+	info = appendLengthPrefixed(info, []byte(enc))
+	info = appendLengthPrefixed(info, nil)
+	info = appendLengthPrefixed(info, nil)
+	info = appendUint32(info, cekSize*8)
+
+	// A single round of SHA-256 is enough for a 256 bit key.
+	h := sha256.New()
+	h.Write(appendUint32(nil, 1))
+	h.Write(z)
+	h.Write(info)
+	return h.Sum(nil)
+}
+
+func appendLengthPrefixed(b, data []byte) []byte {
+	return append(appendUint32(b, uint32(len(data))), data...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
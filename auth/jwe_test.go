@@ -0,0 +1,144 @@
+package auth_test
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/auth/authmock"
+	"github.com/LUSHDigital/core/test"
+)
+
+func ExampleEncryptedIssuer_Issue() {
+	issuer, err := auth.NewIssuerFromPEM([]byte(`... signing private key ...`), nil)
+	if err != nil {
+		return
+	}
+	encrypted, err := auth.NewEncryptedIssuerFromPEM(issuer, []byte(`... recipient public key ...`))
+	if err != nil {
+		return
+	}
+	encrypted.Issue(auth.Claims{StandardClaims: jwt.StandardClaims{Subject: "user-id"}})
+}
+
+func ExampleParser_DecryptionKey() {
+	parser, err := auth.NewParserFromPEM([]byte(`... signing public key ...`), nil)
+	if err != nil {
+		return
+	}
+	if parser.DecryptionKey, err = auth.PrivateKeyFromPEM([]byte(`... recipient private key ...`)); err != nil {
+		return
+	}
+	var claims auth.Claims
+	parser.Parse(`... jwe ...`, &claims)
+}
+
+func TestEncryptedIssuer_Issue(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func() (*auth.EncryptedIssuer, *auth.Parser)
+		alg   string
+	}{
+		{
+			name:  "rsa",
+			setup: authmock.MustNewRSAEncryptedIssuerAndParser,
+			alg:   auth.EncryptionRSAOAEP,
+		},
+		{
+			name:  "ecdsa",
+			setup: authmock.MustNewECDSAEncryptedIssuerAndParser,
+			alg:   auth.EncryptionECDHES,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			issuer, parser := c.setup()
+			raw, err := issuer.Issue(auth.Claims{
+				StandardClaims: jwt.StandardClaims{Subject: "consumer"},
+				Consumer:       auth.Consumer{ID: "1", Grants: []string{"orders.read"}},
+			})
+			test.Equals(t, nil, err)
+			test.Equals(t, 4, strings.Count(raw, "."))
+
+			var header struct {
+				Algorithm   string `json:"alg"`
+				Encryption  string `json:"enc"`
+				ContentType string `json:"cty"`
+			}
+			decodeSegment(t, strings.Split(raw, ".")[0], &header)
+			test.Equals(t, c.alg, header.Algorithm)
+			test.Equals(t, auth.EncryptionA256GCM, header.Encryption)
+			test.Equals(t, "JWT", header.ContentType)
+
+			var claims auth.Claims
+			test.Equals(t, nil, parser.Parse(raw, &claims))
+			test.Equals(t, "consumer", claims.Subject)
+			test.Equals(t, true, claims.Consumer.HasGrant("orders.read"))
+		})
+	}
+}
+
+func TestParser_Parse_encrypted(t *testing.T) {
+	issuer, parser := authmock.MustNewECDSAEncryptedIssuerAndParser()
+	raw, err := issuer.Issue(jwt.StandardClaims{Subject: "consumer"})
+	test.Equals(t, nil, err)
+
+	t.Run("tampered", func(t *testing.T) {
+		parts := strings.Split(raw, ".")
+		ciphertext := []byte(parts[3])
+		if ciphertext[0] == 'A' {
+			ciphertext[0] = 'B'
+		} else {
+			ciphertext[0] = 'A'
+		}
+		parts[3] = string(ciphertext)
+		test.Equals(t, auth.ErrDecryptionFailed, parser.Parse(strings.Join(parts, "."), &jwt.StandardClaims{}))
+	})
+	t.Run("wrong key", func(t *testing.T) {
+		_, other := authmock.MustNewECDSAEncryptedIssuerAndParser()
+		other.DecryptionKey, _ = authmock.MustNewECDSAKeyPair()
+		test.Equals(t, auth.ErrDecryptionFailed, other.Parse(raw, &jwt.StandardClaims{}))
+	})
+	t.Run("key type mismatch", func(t *testing.T) {
+		_, other := authmock.MustNewRSAEncryptedIssuerAndParser()
+		test.Equals(t, auth.ErrAlgorithmKeyMismatch, other.Parse(raw, &jwt.StandardClaims{}))
+	})
+	t.Run("no decryption key", func(t *testing.T) {
+		plain := *parser
+		plain.DecryptionKey = nil
+		test.Equals(t, auth.ErrTokenEncrypted, plain.Parse(raw, &jwt.StandardClaims{}))
+	})
+	t.Run("not encrypted", func(t *testing.T) {
+		signer, _ := authmock.MustNewEdDSAIssuerAndParser()
+		signed, err := signer.Issue(jwt.StandardClaims{Subject: "consumer"})
+		test.Equals(t, nil, err)
+		test.Equals(t, auth.ErrTokenNotEncrypted, parser.Parse(signed, &jwt.StandardClaims{}))
+	})
+	t.Run("wrong signature", func(t *testing.T) {
+		signer, _ := authmock.MustNewEdDSAIssuerAndParser()
+		signed, err := signer.Issue(jwt.StandardClaims{Subject: "consumer"})
+		test.Equals(t, nil, err)
+		private := parser.DecryptionKey.(interface{ Public() crypto.PublicKey })
+		encrypted, err := auth.Encrypt(signed, private.Public())
+		test.Equals(t, nil, err)
+		test.Equals(t, auth.ErrSignatureInvalid, parser.Parse(encrypted, &jwt.StandardClaims{}))
+	})
+}
+
+func TestEncrypt_notEncryptionKey(t *testing.T) {
+	_, public := authmock.MustNewEdDSAKeyPair()
+	_, err := auth.Encrypt("header.payload.signature", public)
+	test.Equals(t, auth.ErrNotEncryptionKey, err)
+}
+
+func decodeSegment(t *testing.T, segment string, v interface{}) {
+	t.Helper()
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	test.Equals(t, nil, err)
+	test.Equals(t, nil, json.Unmarshal(data, v))
+}
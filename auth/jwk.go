@@ -0,0 +1,247 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"sort"
+)
+
+// KeySet represents a set of public keys identified by their key ID, as found in the "kid" header of a token.
+type KeySet interface {
+	// PublicKey should return the public key with the key ID, or ErrUnknownKeyID when there is none.
+	PublicKey(kid string) (crypto.PublicKey, error)
+}
+
+// JWK represents a public key as a JSON web key (RFC 7517) for an RSA, ECDSA or Ed25519 key.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+
+	// N and E are the modulus and exponent of an RSA key.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Curve, X and Y are the curve and coordinates of an ECDSA key, where an Ed25519 key only has a curve and X.
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// NewJWK creates a JSON web key for signing from a public key, or from the public part of a private key.
+// The algorithm of the key is set to the default algorithm for its type, eg. RS256 for RSA.
+func NewJWK(kid string, key crypto.PublicKey) (JWK, error) {
+	jwk := JWK{
+		KeyID: kid,
+		Use:   "sig",
+	}
+	switch key := publicKey(key).(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encodeBase64(key.N.Bytes())
+		jwk.E = encodeBase64(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = key.Curve.Params().Name
+		jwk.X = encodeBase64(padBytes(key.X.Bytes(), size))
+		jwk.Y = encodeBase64(padBytes(key.Y.Bytes(), size))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encodeBase64(key)
+	default:
+		return JWK{}, ErrNotPublicKey
+	}
+	if algs := Algorithms(key); len(algs) > 0 {
+		jwk.Algorithm = algs[0]
+	}
+	return jwk, nil
+}
+
+// PublicKey derives the public key from the JSON web key.
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBase64(k.N)
+		if err != nil || len(n) == 0 {
+			return nil, ErrInvalidJWK
+		}
+		e, err := decodeBase64(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, ErrInvalidJWK
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, ErrInvalidJWK
+		}
+		x, err := decodeBase64(k.X)
+		if err != nil {
+			return nil, ErrInvalidJWK
+		}
+		y, err := decodeBase64(k.Y)
+		if err != nil {
+			return nil, ErrInvalidJWK
+		}
+		key := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, ErrInvalidJWK
+		}
+		return key, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, ErrInvalidJWK
+		}
+		x, err := decodeBase64(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, ErrInvalidJWK
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, ErrInvalidJWK
+	}
+}
+
+// Thumbprint returns the SHA-256 thumbprint of the JSON web key (RFC 7638), which can be used as its key ID.
+func (k JWK) Thumbprint() (string, error) {
+	if _, err := k.PublicKey(); err != nil {
+		return "", err
+	}
+	var members []byte
+	var err error
+	switch k.KeyType {
+	case "RSA":
+		members, err = json.Marshal(struct {
+			E   string `json:"e"`
+			KTY string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.KeyType, k.N})
+	case "EC":
+		members, err = json.Marshal(struct {
+			CRV string `json:"crv"`
+			KTY string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Curve, k.KeyType, k.X, k.Y})
+	default:
+		members, err = json.Marshal(struct {
+			CRV string `json:"crv"`
+			KTY string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Curve, k.KeyType, k.X})
+	}
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(members)
+	return encodeBase64(sum[:]), nil
+}
+
+// Thumbprint returns the SHA-256 JSON web key thumbprint of a public key, or of the public part of a private key.
+func Thumbprint(key crypto.PublicKey) (string, error) {
+	jwk, err := NewJWK("", key)
+	if err != nil {
+		return "", err
+	}
+	return jwk.Thumbprint()
+}
+
+// JWKS represents a JSON web key set, as published by a service for others to verify its tokens.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWKS creates a JSON web key set from public keys by their key ID.
+func NewJWKS(keys map[string]crypto.PublicKey) (JWKS, error) {
+	set := JWKS{Keys: make([]JWK, 0, len(keys))}
+	for kid, key := range keys {
+		jwk, err := NewJWK(kid, key)
+		if err != nil {
+			return JWKS{}, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].KeyID < set.Keys[j].KeyID
+	})
+	return set, nil
+}
+
+// ParseJWKS will take the JSON encoding of a JSON web key set and check every key in it is valid.
+func ParseJWKS(data []byte) (JWKS, error) {
+	var set JWKS
+	if err := json.Unmarshal(data, &set); err != nil {
+		return JWKS{}, err
+	}
+	for _, k := range set.Keys {
+		if _, err := k.PublicKey(); err != nil {
+			return JWKS{}, err
+		}
+	}
+	return set, nil
+}
+
+// PublicKey returns the public key with the key ID.
+func (s JWKS) PublicKey(kid string) (crypto.PublicKey, error) {
+	for _, k := range s.Keys {
+		if k.KeyID == kid {
+			return k.PublicKey()
+		}
+	}
+	return nil, ErrUnknownKeyID
+}
+
+// publicKey returns the public part of a private key, or the key itself when it is not a private key.
+func publicKey(key interface{}) interface{} {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return &key.PublicKey
+	case *ecdsa.PrivateKey:
+		return &key.PublicKey
+	case ed25519.PrivateKey:
+		return key.Public()
+	default:
+		return key
+	}
+}
+
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
+
+func encodeBase64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
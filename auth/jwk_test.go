@@ -0,0 +1,122 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"encoding/json"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/auth/authmock"
+	"github.com/LUSHDigital/core/test"
+)
+
+func ExampleNewJWKS() {
+	_, public := authmock.MustNewRSAKeyPair()
+	set, err := auth.NewJWKS(map[string]crypto.PublicKey{
+		"2019-10-01": public,
+	})
+	if err != nil {
+		return
+	}
+	json.Marshal(set)
+}
+
+func TestNewJWK(t *testing.T) {
+	_, rsaPublic := authmock.MustNewRSAKeyPair()
+	edPrivate, edPublic := authmock.MustNewEdDSAKeyPair()
+	ecPrivate := mustECDSA(elliptic.P521())
+	cases := []struct {
+		name   string
+		key    interface{}
+		public interface{}
+		kty    string
+		alg    string
+	}{
+		{name: "rsa", key: rsaPublic, public: rsaPublic, kty: "RSA", alg: "RS256"},
+		{name: "ecdsa from private key", key: ecPrivate, public: &ecPrivate.PublicKey, kty: "EC", alg: "ES512"},
+		{name: "ed25519 from private key", key: edPrivate, public: edPublic, kty: "OKP", alg: "EdDSA"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			jwk, err := auth.NewJWK("kid", c.key)
+			test.Equals(t, nil, err)
+			test.Equals(t, c.kty, jwk.KeyType)
+			test.Equals(t, c.alg, jwk.Algorithm)
+			b, err := json.Marshal(jwk)
+			test.Equals(t, nil, err)
+			var decoded auth.JWK
+			test.Equals(t, nil, json.Unmarshal(b, &decoded))
+			pk, err := decoded.PublicKey()
+			test.Equals(t, nil, err)
+			test.Equals(t, c.public, pk)
+		})
+	}
+
+	_, err := auth.NewJWK("kid", []byte("secret"))
+	test.Equals(t, auth.ErrNotPublicKey, err)
+}
+
+func TestParseJWKS(t *testing.T) {
+	// Taken from the examples in RFC 7517 appendix A.1.
+	raw := []byte(`{"keys":[
+		{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","use":"enc","kid":"1"},
+		{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}
+	]}`)
+	set, err := auth.ParseJWKS(raw)
+	test.Equals(t, nil, err)
+	test.Equals(t, 2, len(set.Keys))
+	_, err = set.PublicKey("2011-04-29")
+	test.Equals(t, nil, err)
+	_, err = set.PublicKey("unknown")
+	test.Equals(t, auth.ErrUnknownKeyID, err)
+
+	_, err = auth.ParseJWKS([]byte(`{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ","kid":"1"}]}`))
+	test.Equals(t, auth.ErrInvalidJWK, err)
+}
+
+func TestNewKeySetParser(t *testing.T) {
+	onePrivate, onePublic := authmock.MustNewRSAKeyPair()
+	twoPrivate, twoPublic := authmock.MustNewEdDSAKeyPair()
+	set, err := auth.NewJWKS(map[string]crypto.PublicKey{
+		"one": onePublic,
+		"two": twoPublic,
+	})
+	test.Equals(t, nil, err)
+	parser := auth.NewKeySetParser(set, nil)
+
+	sign := func(kid string, method jwt.SigningMethod, key interface{}) string {
+		token := jwt.NewWithClaims(method, &claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		raw, err := token.SignedString(key)
+		test.Equals(t, nil, err)
+		return raw
+	}
+	cases := []struct {
+		name string
+		raw  string
+		err  error
+	}{
+		{name: "rsa", raw: sign("one", jwt.SigningMethodRS256, onePrivate)},
+		{name: "ed25519", raw: sign("two", auth.SigningMethodEdDSA, twoPrivate)},
+		{name: "missing key id", raw: sign("", jwt.SigningMethodRS256, onePrivate), err: auth.ErrMissingKeyID},
+		{name: "unknown key id", raw: sign("three", jwt.SigningMethodRS256, onePrivate), err: auth.ErrUnknownKeyID},
+		{name: "wrong key", raw: sign("two", jwt.SigningMethodRS256, onePrivate), err: auth.ErrAlgorithmNotAllowed},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var parsed auth.Claims
+			err := parser.Parse(c.raw, &parsed)
+			if c.err == nil {
+				test.Equals(t, nil, err)
+				test.Equals(t, claims.Consumer.ID, parsed.Consumer.ID)
+				return
+			}
+			test.Equals(t, c.err, err)
+		})
+	}
+}
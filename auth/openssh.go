@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/binary"
+	"math/big"
+)
+
+const (
+	opensshMagic = "openssh-key-v1\x00"
+)
+
+// sshReader reads the fields of the SSH wire format (RFC 4251, section 5).
+type sshReader struct {
+	data []byte
+	err  error
+}
+
+func (r *sshReader) uint32() uint32 {
+	if r.err != nil || len(r.data) < 4 {
+		r.err = ErrNotOpenSSHKey
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return v
+}
+
+func (r *sshReader) bytes() []byte {
+	n := r.uint32()
+	if r.err != nil || uint32(len(r.data)) < n {
+		r.err = ErrNotOpenSSHKey
+		return nil
+	}
+	v := r.data[:n]
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *sshReader) string() string {
+	return string(r.bytes())
+}
+
+func (r *sshReader) mpint() *big.Int {
+	return new(big.Int).SetBytes(r.bytes())
+}
+
+// parseOpenSSHPrivateKey parses the contents of an "OPENSSH PRIVATE KEY" PEM block.
+// Encrypted keys are not supported, as they are encrypted using bcrypt which is not in the standard library.
+func parseOpenSSHPrivateKey(data []byte) (crypto.PrivateKey, error) {
+	if !bytes.HasPrefix(data, []byte(opensshMagic)) {
+		return nil, ErrNotOpenSSHKey
+	}
+	r := &sshReader{data: data[len(opensshMagic):]}
+	cipherName, kdfName := r.string(), r.string()
+	r.bytes() // kdf options
+	if n := r.uint32(); r.err == nil && n != 1 {
+		return nil, ErrNotOpenSSHKey
+	}
+	r.bytes() // public key
+	private := &sshReader{data: r.bytes()}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if cipherName != "none" || kdfName != "none" {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+	if check := private.uint32(); check != private.uint32() {
+		return nil, ErrNotOpenSSHKey
+	}
+
+	var key crypto.PrivateKey
+	switch keyType := private.string(); keyType {
+	case "ssh-rsa":
+		n, e, d, _, p, q := private.mpint(), private.mpint(), private.mpint(), private.mpint(), private.mpint(), private.mpint()
+		if private.err != nil || !e.IsInt64() {
+			return nil, ErrNotOpenSSHKey
+		}
+		rsaKey := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{N: n, E: int(e.Int64())},
+			D:         d,
+			Primes:    []*big.Int{p, q},
+		}
+		if err := rsaKey.Validate(); err != nil {
+			return nil, err
+		}
+		rsaKey.Precompute()
+		key = rsaKey
+	case "ecdsa-sha2-nistp256", "ecdsa-sha2-nistp384", "ecdsa-sha2-nistp521":
+		curve, ok := sshCurve(private.string())
+		if !ok {
+			return nil, ErrNotOpenSSHKey
+		}
+		point, d := private.bytes(), private.mpint()
+		x, y := elliptic.Unmarshal(curve, point)
+		if private.err != nil || x == nil {
+			return nil, ErrNotOpenSSHKey
+		}
+		key = &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{Curve: curve, X: x, Y: y},
+			D:         d,
+		}
+	case "ssh-ed25519":
+		private.bytes() // public key
+		seed := private.bytes()
+		if private.err != nil || len(seed) != ed25519.PrivateKeySize {
+			return nil, ErrNotOpenSSHKey
+		}
+		key = ed25519.PrivateKey(append([]byte{}, seed...))
+	default:
+		return nil, ErrNotPrivateKey
+	}
+	if private.err != nil {
+		return nil, private.err
+	}
+	return key, nil
+}
+
+// parseOpenSSHPublicKey parses a public key in the OpenSSH authorized keys format, eg. "ssh-ed25519 AAAA... comment".
+func parseOpenSSHPublicKey(line []byte) (crypto.PublicKey, error) {
+	fields := bytes.Fields(line)
+	if len(fields) < 2 {
+		return nil, ErrNotOpenSSHKey
+	}
+	data, err := base64.StdEncoding.DecodeString(string(fields[1]))
+	if err != nil {
+		return nil, ErrNotOpenSSHKey
+	}
+	r := &sshReader{data: data}
+	keyType := r.string()
+	if keyType != string(fields[0]) {
+		return nil, ErrNotOpenSSHKey
+	}
+
+	var key crypto.PublicKey
+	switch keyType {
+	case "ssh-rsa":
+		e, n := r.mpint(), r.mpint()
+		if r.err != nil || !e.IsInt64() {
+			return nil, ErrNotOpenSSHKey
+		}
+		key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	case "ecdsa-sha2-nistp256", "ecdsa-sha2-nistp384", "ecdsa-sha2-nistp521":
+		curve, ok := sshCurve(r.string())
+		if !ok {
+			return nil, ErrNotOpenSSHKey
+		}
+		x, y := elliptic.Unmarshal(curve, r.bytes())
+		if r.err != nil || x == nil {
+			return nil, ErrNotOpenSSHKey
+		}
+		key = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	case "ssh-ed25519":
+		point := r.bytes()
+		if r.err != nil || len(point) != ed25519.PublicKeySize {
+			return nil, ErrNotOpenSSHKey
+		}
+		key = ed25519.PublicKey(append([]byte{}, point...))
+	default:
+		return nil, ErrNotPublicKey
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return key, nil
+}
+
+// sshCurve returns the elliptic curve by its name in the SSH wire format.
+func sshCurve(name string) (elliptic.Curve, bool) {
+	switch name {
+	case "nistp256":
+		return elliptic.P256(), true
+	case "nistp384":
+		return elliptic.P384(), true
+	case "nistp521":
+		return elliptic.P521(), true
+	}
+	return nil, false
+}
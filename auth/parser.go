@@ -2,18 +2,36 @@ package auth
 
 import (
 	"crypto"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
 // Parser represents a set of methods for parsing and validating a JWT against a public key
 type Parser struct {
-	public crypto.PublicKey
-	fn     PublicKeyFunc
+	// Policy is the validation policy every token parsed is checked against.
+	Policy ValidationPolicy
+	// Now returns the current time used to validate tokens, defaulting to time.Now.
+	Now func() time.Time
+	// Revocation is consulted for whether each token has been revoked by its ID, if set.
+	// Tokens without an ID cannot be revoked, which can be prevented by requiring the "jti" claim in the policy.
+	Revocation Revocation
+	// DecryptionKey is the RSA or ECDSA private key used to decrypt tokens, if set.
+	// When set, only encrypted tokens are accepted and each is decrypted before the nested token is verified.
+	DecryptionKey crypto.PrivateKey
+
+	public  crypto.PublicKey
+	keys    KeySet
+	fn      PublicKeyFunc
+	refresh bool
 }
 
-// NewParser returns a new parser with a public key.
+// NewParser returns a new parser with a public key, or a []byte secret for HMAC.
+// When no key function is given, only the algorithms matching the type of the key are allowed.
 func NewParser(pk crypto.PublicKey, fn PublicKeyFunc) *Parser {
+	if fn == nil {
+		fn = AllowAlgorithms(Algorithms(pk)...)
+	}
 	return &Parser{public: pk, fn: fn}
 }
 
@@ -26,14 +44,97 @@ func NewParserFromPEM(key []byte, fn PublicKeyFunc) (*Parser, error) {
 	return NewParser(public, fn), nil
 }
 
+// NewKeySetParser returns a new parser which verifies each token with the key from the set matching its "kid" header.
+// When no key function is given, only the algorithms matching the type of each key are allowed.
+func NewKeySetParser(keys KeySet, fn PublicKeyFunc) *Parser {
+	return &Parser{keys: keys, fn: fn}
+}
+
 // PublicKeyFunc is used to parse tokens using a public key.
 type PublicKeyFunc func(crypto.PublicKey) jwt.Keyfunc
 
-// Parse takes a string and returns a valid jwt token
+// Parse takes a string and returns a valid jwt token.
+// The registered claims are validated by the parser against its policy, using its own clock rather than jwt.TimeFunc.
+// The claims are then validated by their own Valid method, ignoring the time checks of jwt-go already made by the policy.
+// Encrypted tokens are decrypted first when the parser has a decryption key.
+// Refresh tokens are rejected, as they can only be used by a Refresher to issue new tokens.
+// Errors can be inspected with errors.Is, eg. errors.Is(err, auth.ErrTokenExpired).
 func (p *Parser) Parse(raw string, claims jwt.Claims) error {
-	_, err := jwt.ParseWithClaims(raw, claims, p.fn(p.public))
+	raw, err := p.decrypt(raw)
 	if err != nil {
 		return err
 	}
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	if _, err := parser.ParseWithClaims(raw, claims, p.keyfunc); err != nil {
+		return validationError(err)
+	}
+	payload, err := payload(raw)
+	if err != nil {
+		return err
+	}
+	if err := p.Policy.validate(payload, p.now()); err != nil {
+		return err
+	}
+	if use, _ := payload["use"].(string); use == RefreshUse && !p.refresh {
+		return ErrRefreshTokenNotAccepted
+	}
+	if err := claims.Valid(); err != nil && !timeValidationError(err) {
+		return err
+	}
+	if p.Revocation == nil {
+		return nil
+	}
+	jti, _ := payload["jti"].(string)
+	if jti == "" {
+		return nil
+	}
+	revoked, err := p.Revocation.IsRevoked(jti)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return ErrTokenRevoked
+	}
 	return nil
 }
+
+// decrypt returns the token nested in an encrypted token, rejecting encrypted tokens when the parser has no decryption key
+// and plain tokens when it has one.
+func (p *Parser) decrypt(raw string) (string, error) {
+	switch {
+	case p.DecryptionKey == nil && isEncrypted(raw):
+		return "", ErrTokenEncrypted
+	case p.DecryptionKey == nil:
+		return raw, nil
+	case !isEncrypted(raw):
+		return "", ErrTokenNotEncrypted
+	}
+	return Decrypt(raw, p.DecryptionKey)
+}
+
+func (p *Parser) now() time.Time {
+	if p.Now == nil {
+		return time.Now()
+	}
+	return p.Now()
+}
+
+// keyfunc returns the key used to verify a token, looking it up by its key ID when the parser has a key set.
+func (p *Parser) keyfunc(token *jwt.Token) (interface{}, error) {
+	if p.keys == nil {
+		return p.fn(p.public)(token)
+	}
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrMissingKeyID
+	}
+	pk, err := p.keys.PublicKey(kid)
+	if err != nil {
+		return nil, err
+	}
+	fn := p.fn
+	if fn == nil {
+		fn = AllowAlgorithms(Algorithms(pk)...)
+	}
+	return fn(pk)(token)
+}
@@ -1,62 +1,73 @@
 package auth
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 )
 
+const (
+	pemPrivateKey          = "PRIVATE KEY"
+	pemEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
+	pemPublicKey           = "PUBLIC KEY"
+	pemOpenSSHPrivateKey   = "OPENSSH PRIVATE KEY"
+)
+
 // PrivateKeyFromPEM will take a private key PEM and derive the private key from it.
+// Keys can be encoded as PKCS#1, PKCS#8, SEC 1 or in the OpenSSH format.
 func PrivateKeyFromPEM(key []byte) (crypto.PrivateKey, error) {
-	var err error
 	var block *pem.Block
-	var parsed interface{}
 	if block, _ = pem.Decode(key); block == nil {
 		return nil, ErrKeyMustBePEMEncoded
 	}
-	encodings := []encodingFunc{
-		func(der []byte) (interface{}, error) { return x509.ParsePKCS1PrivateKey(der) },
-		func(der []byte) (interface{}, error) { return x509.ParsePKCS8PrivateKey(der) },
-		func(der []byte) (interface{}, error) { return x509.ParseECPrivateKey(der) },
-	}
-	for _, encoding := range encodings {
-		parsed, err = encoding(block.Bytes)
-		if err == nil {
-			break
-		}
+	switch {
+	case block.Type == pemEncryptedPrivateKey, x509.IsEncryptedPEMBlock(block):
+		return nil, ErrKeyEncrypted
+	case block.Type == pemOpenSSHPrivateKey:
+		return parseOpenSSHPrivateKey(block.Bytes)
 	}
-	var private crypto.PrivateKey
-	switch key := parsed.(type) {
-	case *rsa.PrivateKey:
-		private = key
-	case *ecdsa.PrivateKey:
-		private = key
-	default:
-		return nil, ErrNotPrivateKey
-	}
-	return private, nil
+	return parsePrivateKey(block.Bytes)
 }
 
 // PrivateKeyFromPEMWithPassword will take a private key PEM with a password and derive the private key from it.
+// Keys can be encrypted as PKCS#8 using PBES2, or with the legacy PEM encryption (RFC 1423).
 func PrivateKeyFromPEMWithPassword(key []byte, password string) (crypto.PrivateKey, error) {
-	var err error
 	var block *pem.Block
-	var parsed interface{}
 	if block, _ = pem.Decode(key); block == nil {
 		return nil, ErrKeyMustBePEMEncoded
 	}
+	var err error
 	var decrypted []byte
-	if decrypted, err = x509.DecryptPEMBlock(block, []byte(password)); err != nil {
+	switch {
+	case block.Type == pemEncryptedPrivateKey:
+		decrypted, err = decryptPKCS8(block.Bytes, password)
+	case block.Type == pemOpenSSHPrivateKey:
+		return parseOpenSSHPrivateKey(block.Bytes)
+	default:
+		// Legacy PEM encryption is insecure by design, but is still supported for reading existing keys.
+		decrypted, err = x509.DecryptPEMBlock(block, []byte(password))
+	}
+	if err != nil {
 		return nil, err
 	}
+	return parsePrivateKey(decrypted)
+}
+
+// parsePrivateKey parses the DER of an RSA, ECDSA or Ed25519 private key encoded as PKCS#1, PKCS#8 or SEC 1.
+func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
+	var err error
+	var parsed interface{}
 	encodings := []encodingFunc{
 		func(der []byte) (interface{}, error) { return x509.ParsePKCS1PrivateKey(der) },
 		func(der []byte) (interface{}, error) { return x509.ParsePKCS8PrivateKey(der) },
+		func(der []byte) (interface{}, error) { return x509.ParseECPrivateKey(der) },
 	}
 	for _, encoding := range encodings {
-		parsed, err = encoding(decrypted)
+		parsed, err = encoding(der)
 		if err == nil {
 			break
 		}
@@ -65,18 +76,26 @@ func PrivateKeyFromPEMWithPassword(key []byte, password string) (crypto.PrivateK
 	switch key := parsed.(type) {
 	case *rsa.PrivateKey:
 		private = key
+	case *ecdsa.PrivateKey:
+		private = key
+	case ed25519.PrivateKey:
+		private = key
 	default:
-		return nil, ErrNotRSAPrivateKey
+		return nil, ErrNotPrivateKey
 	}
 	return private, nil
 }
 
 // PublicKeyFromPEM will take a public key PEM and derive the public key from it.
+// Keys can be encoded as PKIX, in a certificate or in the OpenSSH authorized keys format.
 func PublicKeyFromPEM(key []byte) (crypto.PublicKey, error) {
 	var err error
 	var block *pem.Block
 	var parsed interface{}
 	if block, _ = pem.Decode(key); block == nil {
+		if bytes.HasPrefix(bytes.TrimSpace(key), []byte("ssh-")) || bytes.HasPrefix(bytes.TrimSpace(key), []byte("ecdsa-")) {
+			return parseOpenSSHPublicKey(key)
+		}
 		return nil, ErrKeyMustBePEMEncoded
 	}
 	if parsed, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
@@ -92,8 +111,57 @@ func PublicKeyFromPEM(key []byte) (crypto.PublicKey, error) {
 		public = key
 	case *ecdsa.PublicKey:
 		public = key
+	case ed25519.PublicKey:
+		public = key
 	default:
 		return nil, ErrNotPublicKey
 	}
 	return public, nil
 }
+
+// PrivateKeyToPEM will take an RSA, ECDSA or Ed25519 private key and encode it as a PKCS#8 PEM.
+func PrivateKeyToPEM(private crypto.PrivateKey) ([]byte, error) {
+	der, err := marshalPrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemPrivateKey, Bytes: der}), nil
+}
+
+// PrivateKeyToPEMWithPassword will take an RSA, ECDSA or Ed25519 private key and encode it as a PKCS#8 PEM
+// encrypted with the password, using PBES2 with PBKDF2 and AES-256-CBC.
+func PrivateKeyToPEMWithPassword(private crypto.PrivateKey, password string) ([]byte, error) {
+	der, err := marshalPrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := encryptPKCS8(der, password)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemEncryptedPrivateKey, Bytes: encrypted}), nil
+}
+
+// PublicKeyToPEM will take an RSA, ECDSA or Ed25519 public key, or the public part of a private key, and encode it as a PKIX PEM.
+func PublicKeyToPEM(public crypto.PublicKey) ([]byte, error) {
+	switch public := publicKey(public).(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(public)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: pemPublicKey, Bytes: der}), nil
+	default:
+		return nil, ErrNotPublicKey
+	}
+}
+
+// marshalPrivateKey encodes an RSA, ECDSA or Ed25519 private key as the DER of PKCS#8.
+func marshalPrivateKey(private crypto.PrivateKey) ([]byte, error) {
+	switch private.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+		return x509.MarshalPKCS8PrivateKey(private)
+	default:
+		return nil, ErrNotPrivateKey
+	}
+}
@@ -0,0 +1,146 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"testing"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/auth/authmock"
+	"github.com/LUSHDigital/core/test"
+)
+
+func ExamplePrivateKeyToPEMWithPassword() {
+	private, _ := authmock.MustNewEdDSAKeyPair()
+	encrypted, err := auth.PrivateKeyToPEMWithPassword(private, "secret")
+	if err != nil {
+		return
+	}
+	auth.PrivateKeyFromPEMWithPassword(encrypted, "secret")
+}
+
+func TestPrivateKeyFromPEMWithPassword_pkcs8(t *testing.T) {
+	for _, name := range []string{"rsa", "ecdsa", "ed25519"} {
+		t.Run(name, func(t *testing.T) {
+			private, err := auth.PrivateKeyFromPEMWithPassword(mustReadFile(t, "testdata/encrypted.pkcs8."+name+".key"), "secret")
+			test.Equals(t, nil, err)
+			public, err := auth.PublicKeyFromPEM(mustReadFile(t, "testdata/encrypted.pkcs8."+name+".pub"))
+			test.Equals(t, nil, err)
+			test.Equals(t, public, private.(interface{ Public() crypto.PublicKey }).Public())
+
+			_, err = auth.PrivateKeyFromPEMWithPassword(mustReadFile(t, "testdata/encrypted.pkcs8."+name+".key"), "wrong")
+			test.Equals(t, x509.IncorrectPasswordError, err)
+			_, err = auth.PrivateKeyFromPEM(mustReadFile(t, "testdata/encrypted.pkcs8."+name+".key"))
+			test.Equals(t, auth.ErrKeyEncrypted, err)
+		})
+	}
+}
+
+func TestPrivateKeyFromPEMWithPassword_pkcs8Parameters(t *testing.T) {
+	cases := []struct {
+		name       string
+		salt       []byte
+		iterations int
+		expected   error
+	}{
+		{name: "no iterations", salt: make([]byte, 16), iterations: 0, expected: auth.ErrInvalidKeyEncryption},
+		{name: "negative iterations", salt: make([]byte, 16), iterations: -1, expected: auth.ErrInvalidKeyEncryption},
+		{name: "too many iterations", salt: make([]byte, 16), iterations: 100000000, expected: auth.ErrInvalidKeyEncryption},
+		{name: "empty salt", salt: []byte{}, iterations: 1, expected: auth.ErrInvalidKeyEncryption},
+		{name: "valid", salt: make([]byte, 16), iterations: 1, expected: x509.IncorrectPasswordError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := auth.PrivateKeyFromPEMWithPassword(mustEncryptedPEM(t, c.salt, c.iterations), "secret")
+			test.Equals(t, c.expected, err)
+		})
+	}
+}
+
+// mustEncryptedPEM encodes a PKCS#8 PEM encrypted using PBES2 with the PBKDF2 parameters, which does not decrypt to a key.
+func mustEncryptedPEM(t *testing.T, salt []byte, iterations int) []byte {
+	marshal := func(v interface{}) asn1.RawValue {
+		der, err := asn1.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return asn1.RawValue{FullBytes: der}
+	}
+	kdf := marshal(struct {
+		Salt           []byte
+		IterationCount int
+	}{salt, iterations})
+	params := marshal(struct {
+		KeyDerivationFunc pkix.AlgorithmIdentifier
+		EncryptionScheme  pkix.AlgorithmIdentifier
+	}{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}, Parameters: kdf},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}, Parameters: marshal(make([]byte, 16))},
+	})
+	info := marshal(struct {
+		Algorithm     pkix.AlgorithmIdentifier
+		EncryptedData []byte
+	}{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}, Parameters: params},
+		EncryptedData: make([]byte, 32),
+	})
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: info.FullBytes})
+}
+
+func TestPrivateKeyFromPEM_openssh(t *testing.T) {
+	for _, name := range []string{"rsa", "ecdsa", "ed25519"} {
+		t.Run(name, func(t *testing.T) {
+			private, err := auth.PrivateKeyFromPEM(mustReadFile(t, "testdata/openssh."+name+".key"))
+			test.Equals(t, nil, err)
+			public, err := auth.PublicKeyFromPEM(mustReadFile(t, "testdata/openssh."+name+".pub"))
+			test.Equals(t, nil, err)
+			test.Equals(t, public, private.(interface{ Public() crypto.PublicKey }).Public())
+		})
+	}
+	t.Run("encrypted", func(t *testing.T) {
+		_, err := auth.PrivateKeyFromPEMWithPassword(mustReadFile(t, "testdata/openssh.encrypted.ed25519.key"), "secret")
+		test.Equals(t, auth.ErrUnsupportedKeyEncryption, err)
+	})
+}
+
+func TestPrivateKeyToPEM(t *testing.T) {
+	rsaPrivate, _ := authmock.MustNewRSAKeyPair()
+	ecdsaPrivate, _ := authmock.MustNewECDSAKeyPair()
+	ed25519Private, _ := authmock.MustNewEdDSAKeyPair()
+	keys := map[string]crypto.PrivateKey{
+		"rsa":     rsaPrivate,
+		"ecdsa":   ecdsaPrivate,
+		"ed25519": ed25519Private,
+	}
+	for name, private := range keys {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := auth.PrivateKeyToPEM(private)
+			test.Equals(t, nil, err)
+			decoded, err := auth.PrivateKeyFromPEM(encoded)
+			test.Equals(t, nil, err)
+			test.Equals(t, private, decoded)
+
+			encoded, err = auth.PublicKeyToPEM(private)
+			test.Equals(t, nil, err)
+			public, err := auth.PublicKeyFromPEM(encoded)
+			test.Equals(t, nil, err)
+			test.Equals(t, private.(interface{ Public() crypto.PublicKey }).Public(), public)
+		})
+	}
+	t.Run("encrypted", func(t *testing.T) {
+		encoded, err := auth.PrivateKeyToPEMWithPassword(ecdsaPrivate, "secret")
+		test.Equals(t, nil, err)
+		decoded, err := auth.PrivateKeyFromPEMWithPassword(encoded, "secret")
+		test.Equals(t, nil, err)
+		test.Equals(t, crypto.PrivateKey(ecdsaPrivate), decoded)
+	})
+	t.Run("not a key", func(t *testing.T) {
+		_, err := auth.PrivateKeyToPEM([]byte("secret"))
+		test.Equals(t, auth.ErrNotPrivateKey, err)
+		_, err = auth.PublicKeyToPEM([]byte("secret"))
+		test.Equals(t, auth.ErrNotPublicKey, err)
+	})
+}
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"hash"
+	"io"
+)
+
+const (
+	// PBKDF2Iterations is the number of PBKDF2 iterations used to derive the key when encrypting a private key.
+	PBKDF2Iterations = 600000
+
+	pbkdf2SaltSize = 16
+	// pbkdf2MaxIterations bounds the work done decrypting a key, as the iteration count is read from the key itself.
+	pbkdf2MaxIterations = 10000000
+)
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// encryptedPrivateKeyInfo represents an encrypted PKCS#8 private key (RFC 5958).
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params represents the parameters of the PBES2 encryption scheme (RFC 8018).
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params represents the parameters of the PBKDF2 key derivation function (RFC 8018).
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// decryptPKCS8 decrypts the DER of an encrypted PKCS#8 private key using PBES2, returning the DER of the private key.
+func decryptPKCS8(der []byte, password string) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) > 0 {
+		return nil, ErrKeyMustBePEMEncoded
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+	if kdf.IterationCount < 1 || kdf.IterationCount > pbkdf2MaxIterations || len(kdf.Salt) == 0 {
+		return nil, ErrInvalidKeyEncryption
+	}
+	prf, ok := pbkdf2PRF(kdf.PRF.Algorithm)
+	if !ok {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+	newCipher, size, ok := pbes2Cipher(params.EncryptionScheme.Algorithm)
+	if !ok {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+	if kdf.KeyLength != 0 && kdf.KeyLength != size {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+
+	block, err := newCipher(pbkdf2Key([]byte(password), kdf.Salt, kdf.IterationCount, size, prf))
+	if err != nil {
+		return nil, err
+	}
+	data := info.EncryptedData
+	if len(iv) != block.BlockSize() || len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, data)
+
+	// A wrong password is only noticed by the padding being invalid.
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > block.BlockSize() ||
+		!bytes.Equal(decrypted[len(decrypted)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, x509.IncorrectPasswordError
+	}
+	return decrypted[:len(decrypted)-padding], nil
+}
+
+// encryptPKCS8 encrypts the DER of a PKCS#8 private key using PBES2 with PBKDF2 and AES-256-CBC.
+func encryptPKCS8(der []byte, password string) ([]byte, error) {
+	salt := make([]byte, pbkdf2SaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(pbkdf2Key([]byte(password), salt, PBKDF2Iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(der)%aes.BlockSize
+	encrypted := append(append([]byte{}, der...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+
+	kdf, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: PBKDF2Iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdf}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: encrypted,
+	})
+}
+
+// pbkdf2PRF returns the pseudorandom function of PBKDF2 by its object identifier, defaulting to HMAC-SHA1.
+func pbkdf2PRF(oid asn1.ObjectIdentifier) (func() hash.Hash, bool) {
+	switch {
+	case len(oid) == 0, oid.Equal(oidHMACWithSHA1):
+		return sha1.New, true
+	case oid.Equal(oidHMACWithSHA256):
+		return sha256.New, true
+	case oid.Equal(oidHMACWithSHA384):
+		return sha512.New384, true
+	case oid.Equal(oidHMACWithSHA512):
+		return sha512.New, true
+	}
+	return nil, false
+}
+
+// pbes2Cipher returns the block cipher of a PBES2 encryption scheme and the size of its key by its object identifier.
+func pbes2Cipher(oid asn1.ObjectIdentifier) (func(key []byte) (cipher.Block, error), int, bool) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return aes.NewCipher, 16, true
+	case oid.Equal(oidAES192CBC):
+		return aes.NewCipher, 24, true
+	case oid.Equal(oidAES256CBC):
+		return aes.NewCipher, 32, true
+	case oid.Equal(oidDESEDE3CBC):
+		return des.NewTripleDESCipher, 24, true
+	}
+	return nil, 0, false
+}
+
+// pbkdf2Key derives a key from a password using PBKDF2 (RFC 8018, section 5.2).
+func pbkdf2Key(password, salt []byte, iterations, size int, h func() hash.Hash) []byte {
+	prf := hmac.New(h, password)
+	var key []byte
+	for block := uint32(1); len(key) < size; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(appendUint32(nil, block))
+		u := prf.Sum(nil)
+		t := append([]byte{}, u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:size]
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// ValidationPolicy represents the checks made on the registered and custom claims of every token parsed.
+// The expiry, not before and issued at claims are always checked when present, allowing for the leeway.
+type ValidationPolicy struct {
+	// Issuers are the issuers accepted, where any issuer is accepted when empty.
+	Issuers []string
+	// Audience is the audience which every token must be intended for, where any audience is accepted when empty.
+	Audience string
+	// Leeway is the clock skew allowed when checking the expiry, not before and issued at claims.
+	Leeway time.Duration
+	// MaxAge is the longest time since a token was issued before it is no longer accepted, where there is no limit when zero.
+	MaxAge time.Duration
+	// RequiredClaims are the names of claims which every token must have, eg. "sub" or "consumer".
+	RequiredClaims []string
+}
+
+// validate checks the claims in the payload of a token against the policy at a given time.
+func (p ValidationPolicy) validate(claims map[string]interface{}, now time.Time) error {
+	for _, name := range p.RequiredClaims {
+		if v, ok := claims[name]; !ok || v == nil {
+			return fmt.Errorf("%w: %s", ErrMissingClaim, name)
+		}
+	}
+
+	exp, ok, err := timeClaim(claims, "exp")
+	if err != nil {
+		return err
+	}
+	if ok && now.After(exp.Add(p.Leeway)) {
+		return ErrTokenExpired
+	}
+	nbf, ok, err := timeClaim(claims, "nbf")
+	if err != nil {
+		return err
+	}
+	if ok && now.Add(p.Leeway).Before(nbf) {
+		return ErrTokenNotValidYet
+	}
+	iat, ok, err := timeClaim(claims, "iat")
+	if err != nil {
+		return err
+	}
+	if ok && now.Add(p.Leeway).Before(iat) {
+		return ErrTokenUsedBeforeIssued
+	}
+	if p.MaxAge > 0 {
+		if !ok {
+			return fmt.Errorf("%w: iat", ErrMissingClaim)
+		}
+		if now.Sub(iat) > p.MaxAge+p.Leeway {
+			return ErrTokenTooOld
+		}
+	}
+
+	if len(p.Issuers) > 0 {
+		iss, _ := claims["iss"].(string)
+		if !contains(p.Issuers, iss) {
+			return ErrWrongIssuer
+		}
+	}
+	if p.Audience != "" && !contains(audience(claims["aud"]), p.Audience) {
+		return ErrWrongAudience
+	}
+	return nil
+}
+
+// payload decodes the claims of a token without verifying them.
+func payload(raw string) (map[string]interface{}, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+	seg, err := jwt.DecodeSegment(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+	var claims map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(seg))
+	dec.UseNumber()
+	if err := dec.Decode(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+	return claims, nil
+}
+
+// timeClaim reads a claim holding the number of seconds since the unix epoch, reporting whether it was present.
+func timeClaim(claims map[string]interface{}, name string) (time.Time, bool, error) {
+	v, ok := claims[name]
+	if !ok || v == nil {
+		return time.Time{}, false, nil
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("%w: %s must be a number", ErrTokenMalformed, name)
+	}
+	if sec, err := n.Int64(); err == nil {
+		return unixTime(float64(sec), 0), true, nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: %s must be a number", ErrTokenMalformed, name)
+	}
+	sec, frac := math.Modf(f)
+	return unixTime(sec, frac), true, nil
+}
+
+// unixTime returns the time from whole seconds and a fraction of a second since the unix epoch.
+// The seconds are limited to a range far beyond any real date, so the time cannot overflow when compared.
+func unixTime(sec, frac float64) time.Time {
+	const limit = 1 << 53
+	switch {
+	case sec > limit:
+		sec, frac = limit, 0
+	case sec < -limit:
+		sec, frac = -limit, 0
+	}
+	return time.Unix(int64(sec), int64(frac*float64(time.Second)))
+}
+
+// audience reads the audience claim, which can either be a single string or a list of strings.
+func audience(v interface{}) []string {
+	switch aud := v.(type) {
+	case string:
+		return []string{aud}
+	case []interface{}:
+		var audiences []string
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				audiences = append(audiences, s)
+			}
+		}
+		return audiences
+	default:
+		return nil
+	}
+}
+
+// timeValidationError reports whether an error from the Valid method of claims only concerns the time claims,
+// which have already been checked by the policy using the clock and leeway of the parser.
+func timeValidationError(err error) bool {
+	ve, ok := err.(*jwt.ValidationError)
+	return ok && ve.Errors&^(jwt.ValidationErrorExpired|jwt.ValidationErrorNotValidYet|jwt.ValidationErrorIssuedAt) == 0
+}
+
+// validationError maps the errors from parsing and verifying a token to the errors of this package.
+func validationError(err error) error {
+	ve, ok := err.(*jwt.ValidationError)
+	if !ok {
+		return err
+	}
+	switch {
+	case ve.Errors&jwt.ValidationErrorMalformed != 0:
+		return fmt.Errorf("%w: %v", ErrTokenMalformed, ve)
+	case ve.Errors&jwt.ValidationErrorUnverifiable != 0 && ve.Inner != nil:
+		return ve.Inner
+	case ve.Errors&jwt.ValidationErrorSignatureInvalid != 0:
+		return ErrSignatureInvalid
+	default:
+		return err
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
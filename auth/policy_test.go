@@ -0,0 +1,217 @@
+package auth_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/test"
+)
+
+func ExampleValidationPolicy() {
+	parser, err := auth.NewParserFromPEM([]byte(`... public key ...`), nil)
+	if err != nil {
+		return
+	}
+	parser.Policy = auth.ValidationPolicy{
+		Issuers:        []string{"auth-service"},
+		Audience:       "payments-service",
+		Leeway:         30 * time.Second,
+		MaxAge:         24 * time.Hour,
+		RequiredClaims: []string{"sub"},
+	}
+	var claims jwt.StandardClaims
+	err = parser.Parse(`... jwt ...`, &claims)
+	switch {
+	case errors.Is(err, auth.ErrTokenExpired):
+		return
+	case err != nil:
+		return
+	}
+}
+
+func TestParser_Parse_policy(t *testing.T) {
+	issued := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	standard := jwt.StandardClaims{
+		Issuer:    "auth",
+		Audience:  "payments",
+		Subject:   "consumer",
+		IssuedAt:  issued.Unix(),
+		NotBefore: issued.Unix(),
+		ExpiresAt: issued.Add(time.Hour).Unix(),
+	}
+	cases := []struct {
+		name   string
+		claims interface{}
+		policy auth.ValidationPolicy
+		now    time.Time
+		err    error
+	}{
+		{
+			name:   "valid",
+			claims: standard,
+			policy: auth.ValidationPolicy{Issuers: []string{"other", "auth"}, Audience: "payments", RequiredClaims: []string{"sub"}},
+			now:    issued.Add(time.Minute),
+		},
+		{
+			name:   "expired",
+			claims: standard,
+			now:    issued.Add(time.Hour + time.Second),
+			err:    auth.ErrTokenExpired,
+		},
+		{
+			name:   "expired within leeway",
+			claims: standard,
+			policy: auth.ValidationPolicy{Leeway: time.Minute},
+			now:    issued.Add(time.Hour + time.Second),
+		},
+		{
+			name:   "not valid yet",
+			claims: jwt.StandardClaims{NotBefore: issued.Add(time.Minute).Unix()},
+			now:    issued,
+			err:    auth.ErrTokenNotValidYet,
+		},
+		{
+			name:   "used before issued",
+			claims: standard,
+			now:    issued.Add(-time.Second),
+			err:    auth.ErrTokenNotValidYet,
+		},
+		{
+			name:   "issued in the future",
+			claims: jwt.StandardClaims{IssuedAt: issued.Unix()},
+			now:    issued.Add(-time.Second),
+			err:    auth.ErrTokenUsedBeforeIssued,
+		},
+		{
+			name:   "too old",
+			claims: jwt.StandardClaims{IssuedAt: issued.Unix()},
+			policy: auth.ValidationPolicy{MaxAge: time.Hour},
+			now:    issued.Add(2 * time.Hour),
+			err:    auth.ErrTokenTooOld,
+		},
+		{
+			name:   "max age without issued at",
+			claims: jwt.StandardClaims{Subject: "consumer"},
+			policy: auth.ValidationPolicy{MaxAge: time.Hour},
+			now:    issued,
+			err:    auth.ErrMissingClaim,
+		},
+		{
+			name:   "wrong issuer",
+			claims: standard,
+			policy: auth.ValidationPolicy{Issuers: []string{"other"}},
+			now:    issued,
+			err:    auth.ErrWrongIssuer,
+		},
+		{
+			name:   "wrong audience",
+			claims: standard,
+			policy: auth.ValidationPolicy{Audience: "orders"},
+			now:    issued,
+			err:    auth.ErrWrongAudience,
+		},
+		{
+			name:   "audience list",
+			claims: jwt.MapClaims{"aud": []string{"orders", "payments"}},
+			policy: auth.ValidationPolicy{Audience: "payments"},
+			now:    issued,
+		},
+		{
+			name:   "never expires",
+			claims: jwt.StandardClaims{ExpiresAt: 9999999999},
+			now:    issued,
+		},
+		{
+			name:   "fractional expiry",
+			claims: jwt.MapClaims{"exp": 9999999999.5},
+			now:    issued,
+		},
+		{
+			name:   "expiry beyond any date",
+			claims: jwt.MapClaims{"exp": 1e300},
+			now:    issued,
+		},
+		{
+			name:   "fractional expiry passed",
+			claims: jwt.MapClaims{"exp": float64(issued.Unix()) + 0.5},
+			now:    issued.Add(time.Second),
+			err:    auth.ErrTokenExpired,
+		},
+		{
+			name:   "missing custom claim",
+			claims: standard,
+			policy: auth.ValidationPolicy{RequiredClaims: []string{"sub", "tenant"}},
+			now:    issued,
+			err:    auth.ErrMissingClaim,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var raw string
+			var err error
+			switch claims := c.claims.(type) {
+			case jwt.StandardClaims:
+				raw, err = issuer.Issue(&claims)
+			case jwt.MapClaims:
+				raw, err = issuer.Issue(claims)
+			}
+			test.Equals(t, nil, err)
+			p := *parser
+			p.Policy = c.policy
+			p.Now = func() time.Time { return c.now }
+			err = p.Parse(raw, &jwt.MapClaims{})
+			test.Equals(t, true, errors.Is(err, c.err))
+		})
+	}
+}
+
+func TestParser_Parse_errors(t *testing.T) {
+	raw, err := invalidIssuer.Issue(&claims)
+	test.Equals(t, nil, err)
+	var parsed auth.Claims
+	test.Equals(t, auth.ErrSignatureInvalid, parser.Parse(raw, &parsed))
+	test.Equals(t, true, errors.Is(parser.Parse("not a token", &parsed), auth.ErrTokenMalformed))
+}
+
+var errWrongTenant = errors.New("wrong tenant")
+
+type tenantClaims struct {
+	jwt.StandardClaims
+	Tenant string `json:"tenant"`
+}
+
+func (c tenantClaims) Valid() error {
+	if err := c.StandardClaims.Valid(); err != nil {
+		return err
+	}
+	if c.Tenant != "lush" {
+		return errWrongTenant
+	}
+	return nil
+}
+
+func TestParser_Parse_claimsValid(t *testing.T) {
+	issued := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	p := *parser
+	p.Now = func() time.Time { return issued }
+
+	raw, err := issuer.Issue(tenantClaims{Tenant: "other"})
+	test.Equals(t, nil, err)
+	test.Equals(t, errWrongTenant, p.Parse(raw, &tenantClaims{}))
+
+	raw, err = issuer.Issue(tenantClaims{Tenant: "lush"})
+	test.Equals(t, nil, err)
+	test.Equals(t, nil, p.Parse(raw, &tenantClaims{}))
+
+	// The time claims are checked against the clock of the parser, even though they have passed in real time.
+	raw, err = issuer.Issue(tenantClaims{
+		StandardClaims: jwt.StandardClaims{ExpiresAt: issued.Add(time.Hour).Unix()},
+		Tenant:         "lush",
+	})
+	test.Equals(t, nil, err)
+	test.Equals(t, nil, p.Parse(raw, &tenantClaims{}))
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/LUSHDigital/uuid"
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// RefreshUse is the value of the "use" claim in every refresh token.
+	RefreshUse = "refresh"
+)
+
+// TokenIssuer represents an issuer of tokens, eg. an Issuer or RotatingIssuer.
+type TokenIssuer interface {
+	Issue(claims jwt.Claims) (string, error)
+}
+
+// RefreshClaims represents the claims of a refresh token.
+// Every token rotated from the same original token shares its family.
+type RefreshClaims struct {
+	jwt.StandardClaims
+	Family string `json:"fam"`
+	Use    string `json:"use"`
+}
+
+// NewRefresher returns a refresher issuing refresh tokens valid for a duration,
+// verifying them with the parser and recording used tokens in the revocation store.
+func NewRefresher(issuer TokenIssuer, parser *Parser, store Revocation, valid time.Duration) *Refresher {
+	return &Refresher{
+		Issuer:     issuer,
+		Parser:     parser,
+		Revocation: store,
+		Valid:      valid,
+		Now:        time.Now,
+	}
+}
+
+// Refresher issues and rotates refresh tokens.
+// Refresh tokens carry the "use" claim set to "refresh", and are rejected by parsers so they cannot be used for access,
+// even when they are signed by the same issuer as access tokens.
+// Every refresh token can only be used once, and using one a second time revokes every token in its family,
+// so a leaked refresh token stops working as soon as either the thief or the user uses it after the other.
+type Refresher struct {
+	// Issuer signs the refresh tokens.
+	Issuer TokenIssuer
+	// Parser verifies the refresh tokens.
+	Parser *Parser
+	// Revocation records the used refresh tokens and revoked families.
+	Revocation Revocation
+	// Valid is how long each refresh token is valid for.
+	Valid time.Duration
+	// Now returns the current time, defaulting to time.Now.
+	Now func() time.Time
+}
+
+// Issue issues a refresh token for the subject, starting a new family.
+func (r *Refresher) Issue(subject string) (string, error) {
+	family, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	raw, _, err := r.issue(subject, family)
+	return raw, err
+}
+
+// Refresh verifies a refresh token and rotates it, returning a new refresh token in the same family and its claims.
+// The refresh token used cannot be used again, and trying to do so revokes the whole family.
+func (r *Refresher) Refresh(raw string) (string, RefreshClaims, error) {
+	claims, err := r.parse(raw)
+	if err != nil {
+		return "", RefreshClaims{}, err
+	}
+	revoked, err := r.Revocation.IsRevoked(claims.Family)
+	if err != nil {
+		return "", RefreshClaims{}, err
+	}
+	if revoked {
+		return "", RefreshClaims{}, ErrTokenRevoked
+	}
+	if claims.Id == "" {
+		return "", RefreshClaims{}, fmt.Errorf("%w: jti", ErrMissingClaim)
+	}
+	used, err := r.Revocation.Revoke(claims.Id, time.Unix(claims.ExpiresAt, 0))
+	if err != nil {
+		return "", RefreshClaims{}, err
+	}
+	if used {
+		// The newest token in the family expires at the latest after the validity period from now.
+		if _, err := r.Revocation.Revoke(claims.Family, r.now().Add(r.Valid)); err != nil {
+			return "", RefreshClaims{}, err
+		}
+		return "", RefreshClaims{}, ErrRefreshTokenReused
+	}
+	return r.issue(claims.Subject, claims.Family)
+}
+
+// Revoke verifies a refresh token and revokes every token in its family, eg. when the user logs out.
+func (r *Refresher) Revoke(raw string) error {
+	claims, err := r.parse(raw)
+	if err != nil {
+		return err
+	}
+	_, err = r.Revocation.Revoke(claims.Family, r.now().Add(r.Valid))
+	return err
+}
+
+// parse verifies a refresh token and returns its claims.
+// Tokens are checked against the revocation store by the refresher itself, to tell reused tokens apart.
+func (r *Refresher) parse(raw string) (RefreshClaims, error) {
+	parser := *r.Parser
+	parser.Revocation = nil
+	parser.refresh = true
+	var claims RefreshClaims
+	if err := parser.Parse(raw, &claims); err != nil {
+		return RefreshClaims{}, err
+	}
+	if claims.Use != RefreshUse {
+		return RefreshClaims{}, ErrNotRefreshToken
+	}
+	if claims.Family == "" {
+		return RefreshClaims{}, fmt.Errorf("%w: fam", ErrMissingClaim)
+	}
+	return claims, nil
+}
+
+// issue issues a refresh token for the subject in a family.
+func (r *Refresher) issue(subject, family string) (string, RefreshClaims, error) {
+	id, err := newTokenID()
+	if err != nil {
+		return "", RefreshClaims{}, err
+	}
+	now := r.now()
+	claims := RefreshClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        id,
+			Subject:   subject,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(r.Valid).Unix(),
+		},
+		Family: family,
+		Use:    RefreshUse,
+	}
+	raw, err := r.Issuer.Issue(claims)
+	if err != nil {
+		return "", RefreshClaims{}, err
+	}
+	return raw, claims, nil
+}
+
+func (r *Refresher) now() time.Time {
+	if r.Now == nil {
+		return time.Now()
+	}
+	return r.Now()
+}
+
+// newTokenID generates a random ID for a token.
+func newTokenID() (string, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
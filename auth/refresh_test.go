@@ -0,0 +1,112 @@
+package auth_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/auth/authmock"
+	"github.com/LUSHDigital/core/test"
+)
+
+func ExampleRefresher_Refresh() {
+	issuer, parser := authmock.MustNewEdDSAIssuerAndParser()
+	refresher := auth.NewRefresher(issuer, parser, auth.NewMemoryRevocation(), 30*24*time.Hour)
+
+	token, err := refresher.Issue("user-id")
+	if err != nil {
+		return
+	}
+	token, claims, err := refresher.Refresh(token)
+	switch {
+	case errors.Is(err, auth.ErrRefreshTokenReused):
+		return
+	case err != nil:
+		return
+	}
+	issuer.Issue(auth.Claims{StandardClaims: jwt.StandardClaims{Subject: claims.Subject}})
+}
+
+func newRefresher() *auth.Refresher {
+	issuer, parser := authmock.MustNewEdDSAIssuerAndParser()
+	return auth.NewRefresher(issuer, parser, auth.NewMemoryRevocation(), time.Hour)
+}
+
+func TestRefresher_Refresh(t *testing.T) {
+	refresher := newRefresher()
+	first, err := refresher.Issue("consumer")
+	test.Equals(t, nil, err)
+
+	second, claims, err := refresher.Refresh(first)
+	test.Equals(t, nil, err)
+	test.Equals(t, "consumer", claims.Subject)
+	test.Equals(t, auth.RefreshUse, claims.Use)
+	test.NotEquals(t, first, second)
+
+	third, next, err := refresher.Refresh(second)
+	test.Equals(t, nil, err)
+	test.Equals(t, claims.Family, next.Family)
+	test.NotEquals(t, claims.Id, next.Id)
+	test.NotEquals(t, "", third)
+}
+
+func TestRefresher_Refresh_reused(t *testing.T) {
+	refresher := newRefresher()
+	first, err := refresher.Issue("consumer")
+	test.Equals(t, nil, err)
+	second, _, err := refresher.Refresh(first)
+	test.Equals(t, nil, err)
+
+	_, _, err = refresher.Refresh(first)
+	test.Equals(t, auth.ErrRefreshTokenReused, err)
+
+	_, _, err = refresher.Refresh(second)
+	test.Equals(t, auth.ErrTokenRevoked, err)
+
+	other, err := refresher.Issue("consumer")
+	test.Equals(t, nil, err)
+	_, _, err = refresher.Refresh(other)
+	test.Equals(t, nil, err)
+}
+
+func TestRefresher_Revoke(t *testing.T) {
+	refresher := newRefresher()
+	first, err := refresher.Issue("consumer")
+	test.Equals(t, nil, err)
+	second, _, err := refresher.Refresh(first)
+	test.Equals(t, nil, err)
+
+	test.Equals(t, nil, refresher.Revoke(second))
+	_, _, err = refresher.Refresh(second)
+	test.Equals(t, auth.ErrTokenRevoked, err)
+}
+
+func TestRefresher_Refresh_notRefreshToken(t *testing.T) {
+	refresher := newRefresher()
+	access, err := refresher.Issuer.Issue(jwt.StandardClaims{Id: "token", Subject: "consumer"})
+	test.Equals(t, nil, err)
+	_, _, err = refresher.Refresh(access)
+	test.Equals(t, auth.ErrNotRefreshToken, err)
+}
+
+func TestRefresher_Refresh_expired(t *testing.T) {
+	refresher := newRefresher()
+	refresher.Now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	expired, err := refresher.Issue("consumer")
+	test.Equals(t, nil, err)
+	_, _, err = refresher.Refresh(expired)
+	test.Equals(t, auth.ErrTokenExpired, err)
+}
+
+func TestParser_Parse_refreshToken(t *testing.T) {
+	refresher := newRefresher()
+	raw, err := refresher.Issue("consumer")
+	test.Equals(t, nil, err)
+	test.Equals(t, auth.ErrRefreshTokenNotAccepted, refresher.Parser.Parse(raw, &auth.Claims{}))
+
+	_, _, err = refresher.Refresh(raw)
+	test.Equals(t, nil, err)
+}
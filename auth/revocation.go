@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	purgeInterval = time.Minute
+)
+
+// Revocation represents a store of revoked tokens, by their ID from the "jti" claim.
+type Revocation interface {
+	// Revoke should revoke the token with the ID until it expires, reporting whether it had already been revoked.
+	Revoke(jti string, expiresAt time.Time) (bool, error)
+	// IsRevoked should report whether the token with the ID has been revoked.
+	IsRevoked(jti string) (bool, error)
+}
+
+// NewMemoryRevocation creates an in-memory store of revoked tokens.
+func NewMemoryRevocation() *MemoryRevocation {
+	return &MemoryRevocation{
+		Now:     time.Now,
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// MemoryRevocation stores revoked tokens in memory, forgetting each token once it would have expired.
+// It is only suitable for a single instance of a service, as revoked tokens are not shared between instances.
+type MemoryRevocation struct {
+	// Now returns the current time, used to forget tokens once they have expired.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	revoked  map[string]time.Time
+	purgedAt time.Time
+}
+
+// Revoke revokes the token with the ID until it expires, reporting whether it had already been revoked.
+func (m *MemoryRevocation) Revoke(jti string, expiresAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.Now()
+	m.purge(now)
+	until, revoked := m.revoked[jti]
+	revoked = revoked && now.Before(until)
+	if !revoked || expiresAt.After(until) {
+		m.revoked[jti] = expiresAt
+	}
+	return revoked, nil
+}
+
+// IsRevoked reports whether the token with the ID has been revoked.
+func (m *MemoryRevocation) IsRevoked(jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	until, ok := m.revoked[jti]
+	return ok && m.Now().Before(until), nil
+}
+
+// purge forgets the tokens which have expired, at most once every purge interval.
+func (m *MemoryRevocation) purge(now time.Time) {
+	if now.Sub(m.purgedAt) < purgeInterval {
+		return
+	}
+	for jti, until := range m.revoked {
+		if !now.Before(until) {
+			delete(m.revoked, jti)
+		}
+	}
+	m.purgedAt = now
+}
@@ -0,0 +1,61 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/auth/authmock"
+	"github.com/LUSHDigital/core/test"
+)
+
+func TestMemoryRevocation(t *testing.T) {
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := auth.NewMemoryRevocation()
+	store.Now = func() time.Time { return now }
+
+	revoked, err := store.IsRevoked("token")
+	test.Equals(t, nil, err)
+	test.Equals(t, false, revoked)
+
+	revoked, err = store.Revoke("token", now.Add(time.Hour))
+	test.Equals(t, nil, err)
+	test.Equals(t, false, revoked)
+
+	revoked, err = store.IsRevoked("token")
+	test.Equals(t, nil, err)
+	test.Equals(t, true, revoked)
+
+	revoked, err = store.Revoke("token", now.Add(time.Hour))
+	test.Equals(t, nil, err)
+	test.Equals(t, true, revoked)
+
+	now = now.Add(time.Hour)
+	revoked, err = store.IsRevoked("token")
+	test.Equals(t, nil, err)
+	test.Equals(t, false, revoked)
+
+	revoked, err = store.Revoke("token", now.Add(time.Hour))
+	test.Equals(t, nil, err)
+	test.Equals(t, false, revoked)
+}
+
+func TestParser_Parse_revoked(t *testing.T) {
+	issuer, parser := authmock.MustNewEdDSAIssuerAndParser()
+	store := auth.NewMemoryRevocation()
+	parser.Revocation = store
+
+	expires := time.Now().Add(time.Hour)
+	revokable, err := issuer.Issue(jwt.StandardClaims{Id: "token", ExpiresAt: expires.Unix()})
+	test.Equals(t, nil, err)
+	anonymous, err := issuer.Issue(jwt.StandardClaims{ExpiresAt: expires.Unix()})
+	test.Equals(t, nil, err)
+
+	test.Equals(t, nil, parser.Parse(revokable, &jwt.StandardClaims{}))
+	_, err = store.Revoke("token", expires)
+	test.Equals(t, nil, err)
+	test.Equals(t, auth.ErrTokenRevoked, parser.Parse(revokable, &jwt.StandardClaims{}))
+	test.Equals(t, nil, parser.Parse(anonymous, &jwt.StandardClaims{}))
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"crypto"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// NewRotatingIssuer creates an issuer without a signing key, keeping retired keys for verification for the window.
+// The window should be at least as long as the tokens issued are valid for, so they can be verified until they expire.
+func NewRotatingIssuer(window time.Duration) *RotatingIssuer {
+	return &RotatingIssuer{
+		Window: window,
+		Now:    time.Now,
+	}
+}
+
+// RotatingIssuer signs tokens with its current key, stamping its key ID in the "kid" header of every token.
+// Rotating to a new key retires the current key, which can still be used to verify tokens until the window has passed.
+// It can be used as the key set of a parser, or published as a JSON web key set for others to verify its tokens.
+type RotatingIssuer struct {
+	// Window is how long a retired key can still be used to verify tokens.
+	Window time.Duration
+	// Now returns the current time, used to schedule rotations and retire keys.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	current   *rotatingKey
+	retired   []*rotatingKey
+	scheduled []*rotatingKey
+}
+
+type rotatingKey struct {
+	issuer *Issuer
+	public crypto.PublicKey
+	// at is when a scheduled key takes over, or when a retired key was retired.
+	at time.Time
+}
+
+func newRotatingKey(kid string, private crypto.PrivateKey, method jwt.SigningMethod, at time.Time) *rotatingKey {
+	return &rotatingKey{
+		issuer: NewIssuerWithKeyID(kid, private, method),
+		public: publicKey(private),
+		at:     at,
+	}
+}
+
+// Rotate starts signing tokens with a new key straight away, retiring the current key.
+// Rotating to the key ID of the current key does nothing.
+func (r *RotatingIssuer) Rotate(kid string, private crypto.PrivateKey, method jwt.SigningMethod) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advance()
+	if r.current != nil && r.current.issuer.kid == kid {
+		return
+	}
+	r.rotate(newRotatingKey(kid, private, method, r.Now()))
+}
+
+// Schedule rotates to a new key at a later time.
+// The key can be used to verify tokens straight away, so it can be published before tokens are signed with it.
+func (r *RotatingIssuer) Schedule(at time.Time, kid string, private crypto.PrivateKey, method jwt.SigningMethod) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scheduled = append(r.scheduled, newRotatingKey(kid, private, method, at))
+	sort.SliceStable(r.scheduled, func(i, j int) bool {
+		return r.scheduled[i].at.Before(r.scheduled[j].at)
+	})
+	r.advance()
+}
+
+// KeyID returns the key ID of the current key, if any.
+func (r *RotatingIssuer) KeyID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advance()
+	if r.current == nil {
+		return ""
+	}
+	return r.current.issuer.kid
+}
+
+// Issue will sign a JWT with the current key and return its string representation.
+func (r *RotatingIssuer) Issue(claims jwt.Claims) (string, error) {
+	r.mu.Lock()
+	r.advance()
+	current := r.current
+	r.mu.Unlock()
+	if current == nil {
+		return "", ErrNoSigningKey
+	}
+	return current.issuer.Issue(claims)
+}
+
+// PublicKey returns the public key with the key ID, for any key which is current, scheduled or retired within the window.
+func (r *RotatingIssuer) PublicKey(kid string) (crypto.PublicKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advance()
+	for _, k := range r.keys() {
+		if k.issuer.kid == kid {
+			return k.public, nil
+		}
+	}
+	return nil, ErrUnknownKeyID
+}
+
+// JWKS returns the public keys which can be used to verify tokens as a JSON web key set.
+func (r *RotatingIssuer) JWKS() (JWKS, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advance()
+	keys := r.keys()
+	set := JWKS{Keys: make([]JWK, 0, len(keys))}
+	for _, k := range keys {
+		jwk, err := NewJWK(k.issuer.kid, k.public)
+		if err != nil {
+			return JWKS{}, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// keys returns every key which can be used for verification, starting with the current key.
+func (r *RotatingIssuer) keys() []*rotatingKey {
+	var keys []*rotatingKey
+	if r.current != nil {
+		keys = append(keys, r.current)
+	}
+	keys = append(keys, r.scheduled...)
+	return append(keys, r.retired...)
+}
+
+// advance rotates to every scheduled key which is due and forgets retired keys outside of the window.
+func (r *RotatingIssuer) advance() {
+	now := r.Now()
+	for len(r.scheduled) > 0 && !r.scheduled[0].at.After(now) {
+		next := r.scheduled[0]
+		r.scheduled = r.scheduled[1:]
+		r.rotate(next)
+	}
+	retired := r.retired[:0]
+	for _, k := range r.retired {
+		if now.Sub(k.at) < r.Window {
+			retired = append(retired, k)
+		}
+	}
+	r.retired = retired
+}
+
+// rotate makes a key the current key, retiring the previous current key at the time the new key took over.
+func (r *RotatingIssuer) rotate(next *rotatingKey) {
+	if r.current != nil {
+		r.current.at = next.at
+		r.retired = append([]*rotatingKey{r.current}, r.retired...)
+	}
+	r.current = next
+}
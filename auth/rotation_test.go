@@ -0,0 +1,91 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/auth/authmock"
+	"github.com/LUSHDigital/core/test"
+)
+
+func ExampleRotatingIssuer() {
+	private, _ := authmock.MustNewRSAKeyPair()
+	next, _ := authmock.MustNewRSAKeyPair()
+	issuer := auth.NewRotatingIssuer(24 * time.Hour)
+	issuer.Rotate("2019-10-01", private, jwt.SigningMethodRS256)
+	issuer.Schedule(time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC), "2019-11-01", next, jwt.SigningMethodRS256)
+	parser := auth.NewKeySetParser(issuer, nil)
+	_ = parser
+}
+
+func TestRotatingIssuer(t *testing.T) {
+	clock := now
+	issuer := auth.NewRotatingIssuer(time.Hour)
+	issuer.Now = func() time.Time { return clock }
+	parser := auth.NewKeySetParser(issuer, nil)
+	parse := func(raw string) error {
+		var parsed auth.Claims
+		return parser.Parse(raw, &parsed)
+	}
+
+	_, err := issuer.Issue(&claims)
+	test.Equals(t, auth.ErrNoSigningKey, err)
+
+	one, _ := authmock.MustNewRSAKeyPair()
+	two, _ := authmock.MustNewEdDSAKeyPair()
+	three, _ := authmock.MustNewRSAKeyPair()
+	issuer.Rotate("one", one, nil)
+	issuer.Schedule(clock.Add(10*time.Minute), "two", two, nil)
+	test.Equals(t, "one", issuer.KeyID())
+
+	first, err := issuer.Issue(&claims)
+	test.Equals(t, nil, err)
+	token, _, err := new(jwt.Parser).ParseUnverified(first, &auth.Claims{})
+	test.Equals(t, nil, err)
+	test.Equals(t, "one", token.Header["kid"])
+	test.Equals(t, nil, parse(first))
+
+	set, err := issuer.JWKS()
+	test.Equals(t, nil, err)
+	test.Equals(t, 2, len(set.Keys))
+
+	clock = clock.Add(10 * time.Minute)
+	test.Equals(t, "two", issuer.KeyID())
+	second, err := issuer.Issue(&claims)
+	test.Equals(t, nil, err)
+	test.Equals(t, nil, parse(first))
+	test.Equals(t, nil, parse(second))
+
+	clock = clock.Add(30 * time.Minute)
+	issuer.Rotate("three", three, nil)
+	issuer.Rotate("three", one, nil)
+	test.Equals(t, "three", issuer.KeyID())
+	test.Equals(t, nil, parse(first))
+
+	clock = clock.Add(30 * time.Minute)
+	test.Equals(t, auth.ErrUnknownKeyID, parse(first))
+	test.Equals(t, nil, parse(second))
+
+	clock = clock.Add(30 * time.Minute)
+	test.Equals(t, auth.ErrUnknownKeyID, parse(second))
+	set, err = issuer.JWKS()
+	test.Equals(t, nil, err)
+	test.Equals(t, 1, len(set.Keys))
+	test.Equals(t, "three", set.Keys[0].KeyID)
+}
+
+func TestThumbprint(t *testing.T) {
+	// Taken from the example in RFC 7638 section 3.1.
+	jwk := auth.JWK{
+		KeyType: "RSA",
+		N:       "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:       "AQAB",
+		KeyID:   "2011-04-29",
+	}
+	thumbprint, err := jwk.Thumbprint()
+	test.Equals(t, nil, err)
+	test.Equals(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint)
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// startTime is when the process started, as close as the package can tell.
+var startTime = time.Now()
+
+// pseudoRevision matches the commit hash at the end of a pseudo-version, eg. v0.0.0-20191109021931-daa7c04131f5.
+var pseudoRevision = regexp.MustCompile(`-(?:[0-9]+\.)?[0-9]{14}-([0-9a-f]{12})(?:\+incompatible)?$`)
+
+// BuildInfo represents the build and runtime information of a service.
+type BuildInfo struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Version   string    `json:"version"`
+	Revision  string    `json:"revision"`
+	GoVersion string    `json:"go_version"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// BuildInfoSetter represents the behaviour for a worker which accepts the build information of the service running it.
+type BuildInfoSetter interface {
+	// SetBuildInfo should make the worker expose the given build information.
+	SetBuildInfo(BuildInfo)
+}
+
+// BuildInfo returns the build and runtime information of the service.
+// When the version or revision have not been set on the service, they are read from the
+// module information embedded in the binary, if available.
+func (s *Service) BuildInfo() BuildInfo {
+	info := BuildInfo{
+		Name:      s.Name,
+		Type:      s.Type,
+		Version:   s.Version,
+		Revision:  s.Revision,
+		GoVersion: runtime.Version(),
+		StartTime: startTime,
+	}
+	if info.Version != "" && info.Revision != "" {
+		return info
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	version := bi.Main.Version
+	if version == "" || version == "(devel)" {
+		return info
+	}
+	if info.Version == "" {
+		info.Version = version
+	}
+	if m := pseudoRevision.FindStringSubmatch(version); m != nil && info.Revision == "" {
+		info.Revision = m[1]
+	}
+	return info
+}
@@ -0,0 +1,45 @@
+package core_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+)
+
+type described struct {
+	funcs
+	info core.BuildInfo
+}
+
+func (d *described) SetBuildInfo(info core.BuildInfo) {
+	d.info = info
+}
+
+func TestService_BuildInfo(t *testing.T) {
+	service := &core.Service{Name: "test", Type: "service", Version: "1.0.0", Revision: "abcdef"}
+	info := service.BuildInfo()
+	test.Equals(t, "test", info.Name)
+	test.Equals(t, "service", info.Type)
+	test.Equals(t, "1.0.0", info.Version)
+	test.Equals(t, "abcdef", info.Revision)
+	test.Equals(t, runtime.Version(), info.GoVersion)
+	test.Equals(t, false, info.StartTime.IsZero())
+}
+
+func TestService_Run_buildInfo(t *testing.T) {
+	w := &described{funcs: funcs{
+		run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		halt: func(context.Context) error { return nil },
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service := &core.Service{Name: "test", Type: "service", Version: "1.0.0", Revision: "abcdef"}
+	service.Run(ctx, w)
+	test.Equals(t, service.BuildInfo(), w.info)
+}
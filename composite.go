@@ -0,0 +1,293 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Listener represents the behaviour for a worker listening on a configured network address.
+type Listener interface {
+	// ListenAddr should return the address the worker is configured to listen on, eg. "0.0.0.0:80".
+	ListenAddr() string
+}
+
+// ErrAddrCollision is returned when workers of a composite are configured to listen on the same address.
+type ErrAddrCollision struct {
+	// Addrs maps every address listened on by more than one worker to those workers, named as service/worker.
+	Addrs map[string][]string
+}
+
+func (e ErrAddrCollision) Error() string {
+	addrs := make([]string, 0, len(e.Addrs))
+	for addr := range e.Addrs {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	collisions := make([]string, len(addrs))
+	for i, addr := range addrs {
+		collisions[i] = fmt.Sprintf("%s (%s)", addr, strings.Join(e.Addrs[addr], ", "))
+	}
+	return fmt.Sprintf("addresses used by more than one worker: %s", strings.Join(collisions, "; "))
+}
+
+// ServiceResult represents how a single service of a composite ended.
+type ServiceResult struct {
+	// Name is the name of the service.
+	Name string
+	RunResult
+}
+
+// CompositeResult represents how the services of a composite ended.
+type CompositeResult struct {
+	// Err is the error which prevented the composite from starting at all.
+	Err error
+	// Signal is the signal which caused the services to shut down, if any.
+	Signal os.Signal
+	// Forced reports whether a second signal forced the composite to exit before the services had stopped.
+	Forced bool
+	// Services are the results for each service in the order they were added.
+	// When forced to exit, the services which had not stopped yet are only reported as forced.
+	Services []ServiceResult
+}
+
+// ExitCode maps the results of every service to a single process exit code, following the precedence of RunResult.
+// Being forced to exit counts as services not stopping within their halt deadline.
+func (r CompositeResult) ExitCode() int {
+	merged := RunResult{Err: r.Err}
+	for _, s := range r.Services {
+		if s.Err != nil && merged.Err == nil {
+			merged.Err = s.Err
+		}
+		merged.Workers = append(merged.Workers, s.Workers...)
+	}
+	switch code := merged.ExitCode(); {
+	case r.Forced && (code == ExitCodeOK || code == ExitCodeHaltFailure):
+		return ExitCodeTimeout
+	default:
+		return code
+	}
+}
+
+type composed struct {
+	service *Service
+	workers []Worker
+}
+
+// NewComposite creates a composite for hosting several services in one process.
+func NewComposite() *Composite {
+	return &Composite{}
+}
+
+// Composite hosts several services in one process, each with their own workers, eg. to run a set of services
+// together during development. Signals are handled once for all services, shutting every one of them down.
+type Composite struct {
+	// Signals are the signals which shut down the services, defaulting to DefaultSignals when nil.
+	Signals []os.Signal
+	// ReloadSignals are the signals which reload the workers of every service, defaulting to DefaultReloadSignals when nil.
+	ReloadSignals []os.Signal
+	// Logger is used by the composite and by every service without a logger of its own.
+	Logger Logger
+
+	services []composed
+}
+
+// Add adds a service with its workers to the composite.
+// The service no longer listens for signals itself, the composite handles them instead.
+func (c *Composite) Add(service *Service, workers ...Worker) {
+	c.services = append(c.services, composed{service: service, workers: workers})
+}
+
+// MustRun will run every service and block until they have all stopped, exiting the process with an appropriate status code.
+func (c *Composite) MustRun(ctx context.Context) {
+	os.Exit(c.RunWithResult(ctx).ExitCode())
+}
+
+// Run will run every service and block until they have all stopped, returning the exit code mapped from the result.
+func (c *Composite) Run(ctx context.Context) int {
+	return c.RunWithResult(ctx).ExitCode()
+}
+
+// RunWithResult will run every service and block until they have all stopped, reporting how each of them ended.
+// When any service stops, or a shutdown signal is received, every service is shut down.
+func (c *Composite) RunWithResult(ctx context.Context) CompositeResult {
+	var (
+		res    CompositeResult
+		logger = c.logger()
+	)
+	if err := c.validate(); err != nil {
+		res.Err = err
+		logger.Error("cannot start composite", "error", err)
+		return res
+	}
+	for _, cs := range c.services {
+		cs.service.Signals = []os.Signal{}
+		cs.service.ReloadSignals = []os.Signal{}
+		if cs.service.Logger == nil {
+			cs.service.Logger = c.Logger
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		once     sync.Once
+		shutdown = make(chan struct{})
+		stop     = func() { once.Do(func() { close(shutdown) }) }
+		finished = make(chan struct{})
+		signals  = newSignalHandler(c.signals(), c.reloadSignals(), func(Event) {})
+	)
+	defer close(finished)
+	defer signals.stop()
+	go signals.handle(ctx, logger, shutdown, finished, stop, func() {
+		for _, cs := range c.services {
+			cs.service.reload()
+		}
+	})
+
+	logger.Info(fmt.Sprintf("starting composite of %d services", len(c.services)))
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make([]ServiceResult, len(c.services))
+		stopped = make(chan struct{}, len(c.services))
+	)
+	for i, cs := range c.services {
+		results[i] = ServiceResult{
+			Name:      cs.service.name(),
+			RunResult: RunResult{Forced: true},
+		}
+		wg.Add(1)
+		go func(i int, cs composed) {
+			defer wg.Done()
+			result := cs.service.RunWithResult(ctx, cs.workers...)
+			mu.Lock()
+			results[i].RunResult = result
+			mu.Unlock()
+			stopped <- struct{}{}
+		}(i, cs)
+	}
+	completed := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(completed)
+	}()
+
+	select {
+	case <-shutdown:
+	case <-stopped:
+	}
+	cancel()
+	select {
+	case <-completed:
+	case <-signals.forced:
+		res.Forced = true
+	}
+	mu.Lock()
+	res.Services = append([]ServiceResult{}, results...)
+	mu.Unlock()
+	select {
+	case res.Signal = <-signals.first:
+	default:
+	}
+
+	switch code := res.ExitCode(); {
+	case res.Forced:
+		logger.Error("composite forced to exit before all services had stopped")
+	case code == ExitCodeOK:
+		logger.Info("composite shutdown gracefully...")
+	default:
+		logger.Error("composite shutdown after failure...", "exit_code", code)
+	}
+	return res
+}
+
+// validate checks every service can be run together, listing every worker listening on the same address.
+func (c *Composite) validate() error {
+	if len(c.services) == 0 {
+		return fmt.Errorf("need at least 1 service")
+	}
+	names := make(map[string]bool, len(c.services))
+	type listener struct {
+		name       string
+		host, port string
+	}
+	var listeners []listener
+	for _, cs := range c.services {
+		if err := cs.service.Validate(); err != nil {
+			return err
+		}
+		if names[cs.service.Name] {
+			return fmt.Errorf("cannot run more than one service named %s", cs.service.Name)
+		}
+		names[cs.service.Name] = true
+		for _, w := range cs.workers {
+			l, ok := unwrap(w).(Listener)
+			if !ok {
+				continue
+			}
+			host, port, err := net.SplitHostPort(l.ListenAddr())
+			if err != nil || port == "0" {
+				continue
+			}
+			listeners = append(listeners, listener{
+				name: cs.service.Name + "/" + workerName(w),
+				host: host,
+				port: port,
+			})
+		}
+	}
+
+	collisions := make(map[string][]string)
+	for i, a := range listeners {
+		for _, b := range listeners[i+1:] {
+			if a.port != b.port || (a.host != b.host && !unspecifiedHost(a.host) && !unspecifiedHost(b.host)) {
+				continue
+			}
+			addr := net.JoinHostPort(a.host, a.port)
+			if len(collisions[addr]) == 0 {
+				collisions[addr] = []string{a.name}
+			}
+			collisions[addr] = append(collisions[addr], b.name)
+		}
+	}
+	if len(collisions) > 0 {
+		return ErrAddrCollision{Addrs: collisions}
+	}
+	return nil
+}
+
+func unspecifiedHost(host string) bool {
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+func (c *Composite) logger() Logger {
+	if c.Logger == nil {
+		return DefaultLogger().With("composite", true)
+	}
+	return c.Logger.With("composite", true)
+}
+
+// signals returns the signals which should shut down the services.
+func (c *Composite) signals() []os.Signal {
+	if c.Signals == nil {
+		return DefaultSignals
+	}
+	return c.Signals
+}
+
+// reloadSignals returns the signals which should reload the workers of the services.
+func (c *Composite) reloadSignals() []os.Signal {
+	if c.ReloadSignals == nil {
+		return DefaultReloadSignals
+	}
+	return c.ReloadSignals
+}
@@ -0,0 +1,173 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+	"github.com/LUSHDigital/core/workers/httpsrv"
+)
+
+type listener struct {
+	named
+	addr string
+}
+
+func (l *listener) ListenAddr() string {
+	return l.addr
+}
+
+func blocking(started chan<- struct{}) *funcs {
+	return &funcs{
+		run: func(ctx context.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil
+		},
+		halt: func(context.Context) error { return nil },
+	}
+}
+
+func ExampleComposite() {
+	composite := core.NewComposite()
+	composite.Add(core.NewService("users", "service"),
+		httpsrv.New(&http.Server{Addr: ":8080", Handler: handler}),
+	)
+	composite.Add(core.NewService("orders", "service"),
+		httpsrv.New(&http.Server{Addr: ":8081", Handler: handler}),
+	)
+	composite.MustRun(ctx)
+}
+
+func TestComposite_RunWithResult_signal(t *testing.T) {
+	started := make(chan struct{}, 2)
+	composite := &core.Composite{
+		Signals:       []os.Signal{syscall.SIGUSR1},
+		ReloadSignals: []os.Signal{},
+	}
+	composite.Add(&core.Service{Name: "users", Type: "service"}, blocking(started))
+	composite.Add(&core.Service{Name: "orders", Type: "service"}, blocking(started))
+
+	go func() {
+		<-started
+		<-started
+		raise(t, syscall.SIGUSR1)
+	}()
+
+	res := composite.RunWithResult(context.Background())
+	test.Equals(t, nil, res.Err)
+	test.Equals(t, core.ExitCodeOK, res.ExitCode())
+	test.Equals(t, syscall.SIGUSR1, res.Signal)
+	test.Equals(t, false, res.Forced)
+	test.Equals(t, 2, len(res.Services))
+	test.Equals(t, "users", res.Services[0].Name)
+	test.Equals(t, "orders", res.Services[1].Name)
+}
+
+func TestComposite_RunWithResult_forced(t *testing.T) {
+	started := make(chan struct{}, 2)
+	halting := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	composite := &core.Composite{
+		Signals:       []os.Signal{syscall.SIGUSR1},
+		ReloadSignals: []os.Signal{},
+	}
+	composite.Add(&core.Service{Name: "users", Type: "service"}, blocking(started))
+	composite.Add(&core.Service{Name: "orders", Type: "service", GracePeriod: time.Minute}, &funcs{
+		run: func(context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		},
+		halt: func(context.Context) error {
+			close(halting)
+			<-release
+			return nil
+		},
+	})
+
+	go func() {
+		<-started
+		<-started
+		raise(t, syscall.SIGUSR1)
+		<-halting
+		raise(t, syscall.SIGUSR1)
+	}()
+
+	begin := time.Now()
+	res := composite.RunWithResult(context.Background())
+	test.Equals(t, true, time.Since(begin) < time.Minute)
+	test.Equals(t, true, res.Forced)
+	test.Equals(t, core.ExitCodeTimeout, res.ExitCode())
+	test.Equals(t, 2, len(res.Services))
+	test.Equals(t, "orders", res.Services[1].Name)
+	test.Equals(t, true, res.Services[1].Forced)
+}
+
+func TestComposite_RunWithResult_serviceStopped(t *testing.T) {
+	started := make(chan struct{}, 1)
+	failure := fmt.Errorf("failure")
+	composite := &core.Composite{
+		Signals:       []os.Signal{},
+		ReloadSignals: []os.Signal{},
+	}
+	composite.Add(&core.Service{Name: "users", Type: "service"}, blocking(started))
+	composite.Add(&core.Service{Name: "orders", Type: "service"}, &funcs{
+		run: func(context.Context) error {
+			<-started
+			return failure
+		},
+		halt: func(context.Context) error { return nil },
+	})
+
+	done := make(chan core.CompositeResult)
+	go func() { done <- composite.RunWithResult(context.Background()) }()
+	select {
+	case res := <-done:
+		test.Equals(t, core.ExitCodeFailure, res.ExitCode())
+		test.Equals(t, core.ExitCodeOK, res.Services[0].ExitCode())
+		test.Equals(t, core.ExitCodeFailure, res.Services[1].ExitCode())
+	case <-time.After(5 * time.Second):
+		t.Fatal("composite did not stop every service when one of them stopped")
+	}
+}
+
+func TestComposite_RunWithResult_addrCollision(t *testing.T) {
+	noop := funcs{
+		run:  func(context.Context) error { return nil },
+		halt: func(context.Context) error { return nil },
+	}
+	composite := core.NewComposite()
+	composite.Add(core.NewService("users", "service"),
+		&listener{named: named{funcs: noop, name: "http server"}, addr: "0.0.0.0:80"},
+		&listener{named: named{funcs: noop, name: "metrics server"}, addr: "0.0.0.0:0"},
+	)
+	composite.Add(core.NewService("orders", "service"),
+		&listener{named: named{funcs: noop, name: "http server"}, addr: "127.0.0.1:80"},
+		&listener{named: named{funcs: noop, name: "grpc server"}, addr: "127.0.0.1:50051"},
+		&listener{named: named{funcs: noop, name: "metrics server"}, addr: "0.0.0.0:0"},
+	)
+
+	res := composite.RunWithResult(context.Background())
+	test.Equals(t, core.ErrAddrCollision{Addrs: map[string][]string{
+		"0.0.0.0:80": {"users/http server", "orders/http server"},
+	}}, res.Err)
+	test.Equals(t, "addresses used by more than one worker: 0.0.0.0:80 (users/http server, orders/http server)", res.Err.Error())
+	test.Equals(t, core.ExitCodeFailure, res.ExitCode())
+	test.Equals(t, 0, len(res.Services))
+}
+
+func TestComposite_RunWithResult_duplicateNames(t *testing.T) {
+	composite := core.NewComposite()
+	composite.Add(core.NewService("users", "service"))
+	composite.Add(core.NewService("users", "service"))
+	res := composite.RunWithResult(context.Background())
+	test.Equals(t, fmt.Errorf("cannot run more than one service named users"), res.Err)
+}
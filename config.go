@@ -0,0 +1,164 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ServiceTypes are the types a service loaded with LoadService can have.
+var ServiceTypes = []string{"service", "aggregator", "gateway"}
+
+// ErrInvalidService is returned when a service is configured incorrectly, listing every problem found.
+type ErrInvalidService struct {
+	Problems []string
+}
+
+func (e ErrInvalidService) Error() string {
+	return fmt.Sprintf("invalid service: %s", strings.Join(e.Problems, "; "))
+}
+
+// serviceConfig represents the configuration of a service as read from a file.
+type serviceConfig struct {
+	Name        string `json:"name" yaml:"name"`
+	Type        string `json:"type" yaml:"type"`
+	Version     string `json:"version" yaml:"version"`
+	Revision    string `json:"revision" yaml:"revision"`
+	GracePeriod string `json:"grace_period" yaml:"grace_period"`
+	DrainDelay  string `json:"drain_delay" yaml:"drain_delay"`
+}
+
+// LoadService creates a new service from an optional YAML or JSON file and the environment, then validates it.
+// When no path is given, the path is read from SERVICE_CONFIG if set. Values from the environment take precedence over the file:
+//
+//	SERVICE_NAME, SERVICE_TYPE, SERVICE_VERSION, SERVICE_REVISION, SERVICE_GRACE_PERIOD and SERVICE_DRAIN_DELAY
+//
+// Durations are written like "10s" or "1m30s". Any problems are returned at once as an ErrInvalidService.
+func LoadService(path string) (*Service, error) {
+	var (
+		problems []string
+		config   serviceConfig
+	)
+	if path == "" {
+		path = os.Getenv("SERVICE_CONFIG")
+	}
+	if path != "" {
+		if err := readServiceConfig(path, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Service{
+		Name:     config.Name,
+		Type:     config.Type,
+		Version:  config.Version,
+		Revision: config.Revision,
+	}
+	if s.Version == "" {
+		s.Version = tag
+	}
+	if s.Revision == "" {
+		s.Revision = ref
+	}
+	setString := func(name string, dst *string) {
+		if v, ok := os.LookupEnv(name); ok {
+			*dst = v
+		}
+	}
+	setString("SERVICE_NAME", &s.Name)
+	setString("SERVICE_TYPE", &s.Type)
+	setString("SERVICE_VERSION", &s.Version)
+	setString("SERVICE_REVISION", &s.Revision)
+
+	setDuration := func(name, field, value string, dst *time.Duration) {
+		if v, ok := os.LookupEnv(name); ok {
+			field, value = name, v
+		}
+		if value == "" {
+			return
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid %s %q", field, value))
+			return
+		}
+		*dst = d
+	}
+	setDuration("SERVICE_GRACE_PERIOD", "grace_period", config.GracePeriod, &s.GracePeriod)
+	setDuration("SERVICE_DRAIN_DELAY", "drain_delay", config.DrainDelay, &s.DrainDelay)
+
+	problems = append(problems, s.problems(ServiceTypes)...)
+	if len(problems) > 0 {
+		return nil, ErrInvalidService{Problems: problems}
+	}
+	return s, nil
+}
+
+func readServiceConfig(path string, config *serviceConfig) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return fmt.Errorf("cannot read service config: unsupported file extension %q", ext)
+	}
+	bts, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read service config: %v", err)
+	}
+	if ext == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(bts))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(config)
+	} else {
+		err = yaml.UnmarshalStrict(bts, config)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read service config %s: %v", path, err)
+	}
+	return nil
+}
+
+// Validate checks the service is configured correctly, returning an ErrInvalidService listing every problem found.
+// Services are validated before they are run.
+func (s *Service) Validate() error {
+	if problems := s.problems(nil); len(problems) > 0 {
+		return ErrInvalidService{Problems: problems}
+	}
+	return nil
+}
+
+// problems lists every problem with the configuration of the service.
+// When types are given, the type of the service must be one of them.
+func (s *Service) problems(types []string) []string {
+	var problems []string
+	if s.Name == "" {
+		problems = append(problems, "missing name")
+	}
+	switch {
+	case s.Type == "":
+		problems = append(problems, "missing type")
+	case types != nil && !contains(types, s.Type):
+		problems = append(problems, fmt.Sprintf("unknown type %q, expected one of %s", s.Type, strings.Join(types, ", ")))
+	}
+	if s.GracePeriod < 0 {
+		problems = append(problems, fmt.Sprintf("negative grace period %s", s.GracePeriod))
+	}
+	if s.DrainDelay < 0 {
+		problems = append(problems, fmt.Sprintf("negative drain delay %s", s.DrainDelay))
+	}
+	return problems
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
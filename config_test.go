@@ -0,0 +1,116 @@
+package core_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+	"github.com/LUSHDigital/core/workers/httpsrv"
+)
+
+func ExampleLoadService() {
+	service, err := core.LoadService("config/service.yaml")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	service.MustRun(ctx, httpsrv.NewDefault(handler))
+}
+
+func setenv(t *testing.T, vars map[string]string) {
+	for k, v := range vars {
+		prev, ok := os.LookupEnv(k)
+		os.Setenv(k, v)
+		k := k
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, prev)
+				return
+			}
+			os.Unsetenv(k)
+		})
+	}
+}
+
+func TestLoadService(t *testing.T) {
+	t.Run("yaml file", func(t *testing.T) {
+		service, err := core.LoadService("testdata/service.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.Equals(t, &core.Service{
+			Name:        "example",
+			Type:        "service",
+			Version:     "1.0.0",
+			Revision:    "abcdef",
+			GracePeriod: 10 * time.Second,
+			DrainDelay:  5 * time.Second,
+		}, service)
+	})
+
+	t.Run("json file with environment", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"SERVICE_TYPE":         "gateway",
+			"SERVICE_VERSION":      "2.0.0",
+			"SERVICE_GRACE_PERIOD": "30s",
+		})
+		service, err := core.LoadService("testdata/service.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.Equals(t, "example", service.Name)
+		test.Equals(t, "gateway", service.Type)
+		test.Equals(t, "2.0.0", service.Version)
+		test.Equals(t, 30*time.Second, service.GracePeriod)
+	})
+
+	t.Run("environment only", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"SERVICE_NAME": "example",
+			"SERVICE_TYPE": "service",
+		})
+		service, err := core.LoadService("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.Equals(t, "example", service.Name)
+		test.Equals(t, time.Duration(0), service.GracePeriod)
+	})
+
+	t.Run("every problem at once", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"SERVICE_DRAIN_DELAY": "later",
+		})
+		_, err := core.LoadService("testdata/invalid.yaml")
+		test.Equals(t, core.ErrInvalidService{Problems: []string{
+			`invalid SERVICE_DRAIN_DELAY "later"`,
+			"missing name",
+			`unknown type "unknown", expected one of service, aggregator, gateway`,
+			"negative grace period -1s",
+		}}, err)
+	})
+
+	t.Run("unsupported file", func(t *testing.T) {
+		_, err := core.LoadService("testdata/service.toml")
+		test.Equals(t, `cannot read service config: unsupported file extension ".toml"`, err.Error())
+	})
+}
+
+func TestService_Validate(t *testing.T) {
+	test.Equals(t, nil, (&core.Service{Name: "test", Type: "service"}).Validate())
+	test.Equals(t, "invalid service: missing name; missing type", (&core.Service{}).Validate().Error())
+	test.Equals(t, nil, (&core.Service{Name: "test", Type: "custom"}).Validate())
+}
+
+func TestService_Run_invalid(t *testing.T) {
+	service := &core.Service{Type: "service", GracePeriod: -time.Second, DrainDelay: -time.Second}
+	res := service.RunWithResult(context.Background(), newWorker("server", &recorder{}))
+	test.Equals(t, core.ErrInvalidService{Problems: []string{
+		"missing name",
+		"negative grace period -1s",
+		"negative drain delay -1s",
+	}}, res.Err)
+}
@@ -0,0 +1,151 @@
+// Package coretest provides a harness for running a core.Service in-process, for use in integration tests.
+package coretest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/LUSHDigital/core"
+)
+
+// Addresser represents the behaviour for a worker listening on a network address, like the servers in core/workers.
+type Addresser interface {
+	// Addr should block until the worker is listening and return the address it listens on.
+	Addr() *net.TCPAddr
+}
+
+type listener struct {
+	worker core.Worker
+	addr   *net.TCPAddr
+}
+
+// Harness represents a service running in the background of a test.
+type Harness struct {
+	tb        testing.TB
+	cancel    context.CancelFunc
+	done      chan struct{}
+	result    core.RunResult
+	listeners []listener
+	once      sync.Once
+}
+
+// Start runs the service with the workers in the background of the test, and waits until every worker
+// implementing Addresser is listening. The test fails if the service stops before that.
+//
+// The service is changed to not listen for any signals unless it has been configured to. Instead it is shut down
+// by calling Shutdown, or at the latest when the test and its subtests have completed.
+func Start(tb testing.TB, service *core.Service, workers ...core.Worker) *Harness {
+	tb.Helper()
+	if service.Signals == nil {
+		service.Signals = []os.Signal{}
+	}
+	if service.ReloadSignals == nil {
+		service.ReloadSignals = []os.Signal{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &Harness{
+		tb:     tb,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(h.done)
+		h.result = service.RunWithResult(ctx, workers...)
+	}()
+	tb.Cleanup(func() { h.Shutdown() })
+
+	for _, w := range workers {
+		a, ok := core.Unwrap(w).(Addresser)
+		if !ok {
+			continue
+		}
+		addr, err := h.listening(a)
+		if err != nil {
+			tb.Fatalf("coretest: %T is not listening: %v", core.Unwrap(w), err)
+		}
+		h.listeners = append(h.listeners, listener{worker: core.Unwrap(w), addr: addr})
+	}
+	return h
+}
+
+// listening waits for the worker to listen, or for the service to stop.
+func (h *Harness) listening(a Addresser) (*net.TCPAddr, error) {
+	addrC := make(chan *net.TCPAddr, 1)
+	go func() { addrC <- a.Addr() }()
+	select {
+	case addr := <-addrC:
+		if addr == nil || addr.Port == 0 {
+			return nil, fmt.Errorf("timed out waiting for an address")
+		}
+		return addr, nil
+	case <-h.done:
+		return nil, fmt.Errorf("service stopped with exit code %d: %v", h.result.ExitCode(), failure(h.result))
+	}
+}
+
+func failure(res core.RunResult) error {
+	if res.Err != nil {
+		return res.Err
+	}
+	for _, w := range res.Failed() {
+		return fmt.Errorf("%s: %v", w.Name, w.RunErr)
+	}
+	return nil
+}
+
+// Addr returns the address the worker is listening on, failing the test if it does not have one.
+// Workers of a type which cannot be compared, like a struct holding a slice, cannot be found and should be passed as a pointer.
+func (h *Harness) Addr(worker core.Worker) *net.TCPAddr {
+	h.tb.Helper()
+	worker = core.Unwrap(worker)
+	for _, l := range h.listeners {
+		if sameWorker(l.worker, worker) {
+			return l.addr
+		}
+	}
+	h.tb.Fatalf("coretest: %T is not listening on an address", worker)
+	return nil
+}
+
+// sameWorker reports whether two workers are the same, without panicking on types which cannot be compared.
+func sameWorker(a, b core.Worker) bool {
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || t == nil || !t.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// URL returns the base URL of an http server, using the loopback address when it listens on all interfaces.
+func (h *Harness) URL(worker core.Worker) string {
+	h.tb.Helper()
+	addr := h.Addr(worker)
+	host := addr.IP.String()
+	if addr.IP == nil || addr.IP.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return fmt.Sprintf("http://%s", net.JoinHostPort(host, fmt.Sprint(addr.Port)))
+}
+
+// Done returns a channel which is closed once the service has stopped.
+func (h *Harness) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait blocks until the service stops by itself and returns its result.
+func (h *Harness) Wait() core.RunResult {
+	<-h.done
+	return h.result
+}
+
+// Shutdown tells the service to shut down, as if it had received a signal, and returns its result once it has stopped.
+// It is safe to call more than once.
+func (h *Harness) Shutdown() core.RunResult {
+	h.once.Do(h.cancel)
+	return h.Wait()
+}
@@ -0,0 +1,111 @@
+package coretest_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/coretest"
+	"github.com/LUSHDigital/core/test"
+	"github.com/LUSHDigital/core/workers/httpsrv"
+	"github.com/LUSHDigital/core/workers/metricsrv"
+)
+
+func newServer() *httpsrv.Server {
+	return httpsrv.New(&http.Server{
+		Addr: "127.0.0.1:0",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte("hello world"))
+		}),
+	})
+}
+
+func ExampleStart() {
+	var t *testing.T
+	server := newServer()
+	h := coretest.Start(t, core.NewService("example", "service"), server)
+	res, err := http.Get(h.URL(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+}
+
+func TestStart(t *testing.T) {
+	server := newServer()
+	metrics := metricsrv.New(&metricsrv.Config{
+		Server: &http.Server{Addr: "127.0.0.1:0"},
+	})
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	h := coretest.Start(t, service, core.DependsOn(server, metrics), metrics)
+
+	res, err := http.Get(h.URL(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Equals(t, "hello world", string(body))
+	test.NotEquals(t, 0, h.Addr(metrics).Port)
+
+	result := h.Shutdown()
+	test.Equals(t, core.ExitCodeOK, result.ExitCode())
+	test.Equals(t, result, h.Shutdown())
+}
+
+func TestHarness_Wait(t *testing.T) {
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	h := coretest.Start(t, service, &stopping{})
+	select {
+	case <-h.Done():
+	case <-time.After(time.Second):
+		t.Fatal("service did not stop by itself")
+	}
+	test.Equals(t, core.ExitCodeOK, h.Wait().ExitCode())
+}
+
+type stopping struct{}
+
+func (s *stopping) Run(context.Context) error  { return nil }
+func (s *stopping) Halt(context.Context) error { return nil }
+
+func TestHarness_Addr_uncomparableWorker(t *testing.T) {
+	tb := &fatalRecorder{TB: t}
+	worker := listening{tags: []string{"uncomparable"}}
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	h := coretest.Start(tb, service, worker)
+	test.Equals(t, (*net.TCPAddr)(nil), h.Addr(worker))
+	test.Equals(t, "coretest: coretest_test.listening is not listening on an address", tb.fatal)
+}
+
+// listening is a worker which cannot be compared, as it holds a slice and is not a pointer.
+type listening struct {
+	tags []string
+}
+
+func (l listening) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+func (l listening) Halt(context.Context) error { return nil }
+func (l listening) Addr() *net.TCPAddr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}
+}
+
+// fatalRecorder records the message of a fatal failure instead of failing the test.
+type fatalRecorder struct {
+	testing.TB
+	fatal string
+}
+
+func (r *fatalRecorder) Fatalf(format string, args ...interface{}) {
+	r.fatal = fmt.Sprintf(format, args...)
+}
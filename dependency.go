@@ -0,0 +1,215 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Dependant represents the behaviour for a worker which depends on other workers.
+type Dependant interface {
+	// DependsOn should return the workers which need to be ready before this worker can be started.
+	DependsOn() []Worker
+}
+
+// DependsOn wraps a worker and declares which workers need to be ready before it can be started.
+func DependsOn(worker Worker, dependencies ...Worker) Worker {
+	return &dependant{
+		Worker:       worker,
+		dependencies: dependencies,
+	}
+}
+
+type dependant struct {
+	Worker
+	dependencies []Worker
+}
+
+// DependsOn returns the workers which need to be ready before this worker can be started.
+func (d *dependant) DependsOn() []Worker {
+	return d.dependencies
+}
+
+func (d *dependant) unwrap() Worker {
+	return d.Worker
+}
+
+// wrapper represents a worker wrapped by the core package to carry extra behaviour for the service.
+type wrapper interface {
+	unwrap() Worker
+}
+
+// Unwrap returns the worker underneath any wrappers added by DependsOn or Supervise,
+// or the worker itself when it has not been wrapped.
+func Unwrap(worker Worker) Worker {
+	return unwrap(worker)
+}
+
+// unwrap returns the worker underneath any wrappers added by the core package.
+func unwrap(worker Worker) Worker {
+	for {
+		w, ok := worker.(wrapper)
+		if !ok {
+			return worker
+		}
+		worker = w.unwrap()
+	}
+}
+
+// dependencies collects the dependencies declared by a worker and any of its wrappers.
+func dependencies(worker Worker) []Worker {
+	var deps []Worker
+	for {
+		if d, ok := worker.(Dependant); ok {
+			deps = append(deps, d.DependsOn()...)
+		}
+		w, ok := worker.(wrapper)
+		if !ok {
+			return deps
+		}
+		worker = w.unwrap()
+	}
+}
+
+// workerName returns a human readable identity for a worker.
+func workerName(worker Worker) string {
+	if n, ok := unwrap(worker).(Named); ok && n.Name() != "" {
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", unwrap(worker))
+}
+
+// ErrDependencyCycle represents an error for when workers depend on each other in a cycle.
+type ErrDependencyCycle struct {
+	Workers []string
+}
+
+func (e ErrDependencyCycle) Error() string {
+	return fmt.Sprintf("dependency cycle detected: %s", strings.Join(e.Workers, " -> "))
+}
+
+// ErrMissingDependency represents an error for when a worker depends on a worker which is not being run.
+type ErrMissingDependency struct {
+	Worker     string
+	Dependency string
+}
+
+func (e ErrMissingDependency) Error() string {
+	return fmt.Sprintf("%s depends on %s which is not being run", e.Worker, e.Dependency)
+}
+
+// stage orders the workers into stages where every worker only depends on workers in earlier stages.
+func stage(units []*unit) ([][]*unit, error) {
+	for _, u := range units {
+		u.deps = u.deps[:0]
+		for _, dep := range dependencies(u.wrapped) {
+			d, ok := find(units, unwrap(dep))
+			if !ok {
+				return nil, ErrMissingDependency{
+					Worker:     u.name,
+					Dependency: workerName(dep),
+				}
+			}
+			u.deps = append(u.deps, d)
+		}
+	}
+
+	var (
+		stages  [][]*unit
+		staged  = make(map[*unit]bool, len(units))
+		pending = units
+	)
+	for len(pending) > 0 {
+		var current, next []*unit
+		for _, u := range pending {
+			ready := true
+			for _, d := range u.deps {
+				if !staged[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				current = append(current, u)
+			} else {
+				next = append(next, u)
+			}
+		}
+		if len(current) == 0 {
+			return nil, ErrDependencyCycle{Workers: cycle(next)}
+		}
+		for _, u := range current {
+			staged[u] = true
+		}
+		stages = append(stages, current)
+		pending = next
+	}
+	return stages, nil
+}
+
+// find returns the unit running a worker.
+func find(units []*unit, worker Worker) (*unit, bool) {
+	for _, u := range units {
+		if sameWorker(u.worker, worker) {
+			return u, true
+		}
+	}
+	return nil, false
+}
+
+// sameWorker reports whether two workers are the same worker.
+// Workers of types which cannot be compared, eg. structs holding a slice passed by value, are never the same,
+// as comparing them would panic.
+func sameWorker(a, b Worker) bool {
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || t == nil || !t.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// cycle finds the names of the workers forming a cycle amongst units which could not be staged.
+func cycle(units []*unit) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	var (
+		state = make(map[*unit]int, len(units))
+		path  []*unit
+		found []string
+		visit func(u *unit) bool
+	)
+	visit = func(u *unit) bool {
+		state[u] = visiting
+		path = append(path, u)
+		for _, d := range u.deps {
+			switch state[d] {
+			case visiting:
+				for i, p := range path {
+					if p == d {
+						for _, c := range path[i:] {
+							found = append(found, c.name)
+						}
+						found = append(found, d.name)
+						return true
+					}
+				}
+			case unvisited:
+				if visit(d) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[u] = visited
+		return false
+	}
+	for _, u := range units {
+		if state[u] == unvisited && visit(u) {
+			return found
+		}
+	}
+	return found
+}
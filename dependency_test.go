@@ -0,0 +1,184 @@
+package core_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+	"github.com/LUSHDigital/core/workers/httpsrv"
+	"github.com/LUSHDigital/core/workers/keybroker"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.events...)
+}
+
+type worker struct {
+	name    string
+	rec     *recorder
+	deps    []core.Worker
+	readyAt time.Time
+	started chan struct{}
+	halted  chan struct{}
+}
+
+func newWorker(name string, rec *recorder, deps ...core.Worker) *worker {
+	return &worker{
+		name:    name,
+		rec:     rec,
+		deps:    deps,
+		started: make(chan struct{}),
+		halted:  make(chan struct{}),
+	}
+}
+
+func (w *worker) Run(_ context.Context) error {
+	w.rec.record(w.name + " run")
+	close(w.started)
+	<-w.halted
+	return nil
+}
+
+func (w *worker) Halt(_ context.Context) error {
+	w.rec.record(w.name + " halt")
+	close(w.halted)
+	return nil
+}
+
+func (w *worker) Check() ([]string, bool) {
+	select {
+	case <-w.started:
+		return nil, time.Now().After(w.readyAt)
+	default:
+		return nil, false
+	}
+}
+
+func (w *worker) DependsOn() []core.Worker {
+	return w.deps
+}
+
+func ExampleDependsOn() {
+	broker := keybroker.NewPublicRSA(nil)
+	service := core.NewService("example", "service")
+	service.MustRun(ctx,
+		broker,
+		core.DependsOn(httpsrv.NewDefault(handler), broker),
+	)
+}
+
+func TestService_Run_dependencies(t *testing.T) {
+	rec := &recorder{}
+	broker := newWorker("broker", rec)
+	broker.readyAt = time.Now().Add(100 * time.Millisecond)
+	cache := newWorker("cache", rec, broker)
+	server := newWorker("server", rec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-server.started
+		cancel()
+	}()
+
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	code := service.Run(ctx,
+		core.DependsOn(server, cache),
+		cache,
+		broker,
+	)
+	test.Equals(t, 0, code)
+	test.Equals(t, []string{
+		"broker run",
+		"cache run",
+		"server run",
+		"server halt",
+		"cache halt",
+		"broker halt",
+	}, rec.recorded())
+}
+
+func TestService_Run_dependencyCycle(t *testing.T) {
+	rec := &recorder{}
+	a := newWorker("a", rec)
+	b := newWorker("b", rec, a)
+	c := newWorker("c", rec, b)
+	a.deps = []core.Worker{c}
+
+	service := &core.Service{Name: "test", Type: "service"}
+	code := service.Run(context.Background(), a, b, c)
+	test.Equals(t, 1, code)
+	test.Equals(t, []string{}, rec.recorded())
+}
+
+func TestService_Run_missingDependency(t *testing.T) {
+	rec := &recorder{}
+	a := newWorker("a", rec)
+	b := newWorker("b", rec, a)
+
+	service := &core.Service{Name: "test", Type: "service"}
+	code := service.Run(context.Background(), b)
+	test.Equals(t, 1, code)
+	test.Equals(t, []string{}, rec.recorded())
+}
+
+type sliceWorker struct {
+	tags   []string
+	halted chan struct{}
+}
+
+func (w sliceWorker) Run(_ context.Context) error {
+	<-w.halted
+	return nil
+}
+
+func (w sliceWorker) Halt(_ context.Context) error {
+	close(w.halted)
+	return nil
+}
+
+func TestService_Run_uncomparableWorker(t *testing.T) {
+	rec := &recorder{}
+	server := newWorker("server", rec)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-server.started
+		cancel()
+	}()
+
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	code := service.Run(ctx,
+		sliceWorker{tags: []string{"a"}, halted: make(chan struct{})},
+		server,
+	)
+	test.Equals(t, 0, code)
+	test.Equals(t, []string{"server run", "server halt"}, rec.recorded())
+}
+
+func TestService_Run_uncomparableDependency(t *testing.T) {
+	rec := &recorder{}
+	server := newWorker("server", rec)
+
+	service := &core.Service{Name: "test", Type: "service"}
+	code := service.Run(context.Background(),
+		sliceWorker{tags: []string{"a"}, halted: make(chan struct{})},
+		core.DependsOn(server, sliceWorker{tags: []string{"a"}, halted: make(chan struct{})}),
+	)
+	test.Equals(t, 1, code)
+	test.Equals(t, []string{}, rec.recorded())
+}
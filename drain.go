@@ -0,0 +1,34 @@
+package core
+
+import (
+	"time"
+)
+
+// Drainer represents the behaviour for a worker which should stop receiving new work before the service halts.
+type Drainer interface {
+	// Drain should make the worker report the service as going away, while it keeps serving what it receives.
+	Drain()
+}
+
+// drain tells every running worker the service is draining and waits for the drain delay to pass.
+// It returns false if exit is forced before the delay has passed.
+func (s *Service) drain(started [][]*unit, completed <-chan struct{}, forced <-chan struct{}) bool {
+	for _, stage := range started {
+		for _, u := range stage {
+			u.drain()
+		}
+	}
+	if s.DrainDelay <= 0 {
+		return true
+	}
+	s.logger().Info("draining", "delay", s.DrainDelay)
+	timer := time.NewTimer(s.DrainDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-completed:
+	case <-forced:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+)
+
+type drainer struct {
+	*worker
+	drainedAt time.Time
+}
+
+func (d *drainer) Drain() {
+	d.drainedAt = time.Now()
+	d.rec.record(d.name + " drain")
+}
+
+func TestService_Run_drain(t *testing.T) {
+	rec := &recorder{}
+	server := &drainer{worker: newWorker("server", rec)}
+	consumer := newWorker("consumer", rec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-consumer.started
+		cancel()
+	}()
+	service := &core.Service{
+		Name:        "test",
+		Type:        "service",
+		GracePeriod: time.Second,
+		DrainDelay:  50 * time.Millisecond,
+	}
+	res := service.RunWithResult(ctx, server, core.DependsOn(consumer, server))
+	test.Equals(t, core.ExitCodeOK, res.ExitCode())
+	test.Equals(t, []string{
+		"server run",
+		"consumer run",
+		"server drain",
+		"consumer halt",
+		"server halt",
+	}, rec.recorded())
+	test.Equals(t, true, time.Since(server.drainedAt) >= 50*time.Millisecond)
+}
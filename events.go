@@ -0,0 +1,121 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// EventType represents a transition in the lifecycle of a service.
+type EventType int
+
+const (
+	// EventServiceStarting happens before the service starts any worker.
+	EventServiceStarting EventType = iota
+	// EventWorkerStarted happens every time a worker is run, including restarts.
+	EventWorkerStarted
+	// EventSignalReceived happens when the service receives a signal it listens for.
+	EventSignalReceived
+	// EventWorkerErrored happens when a worker stops running with an error.
+	EventWorkerErrored
+	// EventWorkerStopped happens every time a worker stops running, with or without an error.
+	EventWorkerStopped
+	// EventWorkerHalted happens when a worker has returned from being told to halt.
+	EventWorkerHalted
+	// EventServiceStopped happens once the service has finished halting its workers.
+	EventServiceStopped
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventServiceStarting:
+		return "service starting"
+	case EventWorkerStarted:
+		return "worker started"
+	case EventSignalReceived:
+		return "signal received"
+	case EventWorkerErrored:
+		return "worker errored"
+	case EventWorkerStopped:
+		return "worker stopped"
+	case EventWorkerHalted:
+		return "worker halted"
+	case EventServiceStopped:
+		return "service stopped"
+	default:
+		return fmt.Sprintf("event(%d)", int(t))
+	}
+}
+
+// Event represents a single transition in the lifecycle of a service.
+type Event struct {
+	// Type is the kind of transition.
+	Type EventType
+	// Time is when the transition happened.
+	Time time.Time
+	// Service is the name of the service.
+	Service string
+	// Worker is the identity of the worker, empty for events of the service itself.
+	Worker string
+	// Signal is the signal received, for EventSignalReceived.
+	Signal os.Signal
+	// Err is the error the worker stopped running or halted with, if any.
+	Err error
+}
+
+// Observer represents the behaviour for reacting to the lifecycle events of a service.
+// Events are delivered synchronously from the goroutine where they happen, so observers must be safe for
+// concurrent use and return quickly.
+type Observer interface {
+	// Observe is called for every lifecycle event.
+	Observe(Event)
+}
+
+// ObserverFunc allows a function to be used as an Observer.
+type ObserverFunc func(Event)
+
+// Observe calls the function with the event.
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+// NewLogObserver returns an observer writing every event to the logger, logging worker errors at error level.
+func NewLogObserver(logger Logger) Observer {
+	return ObserverFunc(func(e Event) {
+		keyvals := []interface{}{"event", e.Type.String()}
+		if e.Worker != "" {
+			keyvals = append(keyvals, "worker", e.Worker)
+		}
+		if e.Signal != nil {
+			keyvals = append(keyvals, "signal", e.Signal)
+		}
+		if e.Err != nil {
+			keyvals = append(keyvals, "error", e.Err)
+			logger.Error("lifecycle event", keyvals...)
+			return
+		}
+		logger.Info("lifecycle event", keyvals...)
+	})
+}
+
+// observers returns the observers of the service, followed by any workers implementing Observer.
+func (s *Service) observers(workers []Worker) []Observer {
+	observers := append([]Observer{}, s.Observers...)
+	for _, w := range workers {
+		if o, ok := unwrap(w).(Observer); ok {
+			observers = append(observers, o)
+		}
+	}
+	return observers
+}
+
+// notify returns a function delivering events to every observer, setting the time and service name of each event.
+func notify(service string, observers []Observer) func(Event) {
+	return func(e Event) {
+		e.Time = time.Now()
+		e.Service = service
+		for _, o := range observers {
+			o.Observe(e)
+		}
+	}
+}
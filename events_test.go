@@ -0,0 +1,150 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+)
+
+type observer struct {
+	mu     sync.Mutex
+	events []core.Event
+}
+
+func (o *observer) Observe(e core.Event) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, e)
+}
+
+func (o *observer) observed() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var observed []string
+	for _, e := range o.events {
+		s := e.Type.String()
+		if e.Worker != "" {
+			s += " " + e.Worker
+		}
+		if e.Signal != nil {
+			s += " " + e.Signal.String()
+		}
+		if e.Err != nil {
+			s += ": " + e.Err.Error()
+		}
+		observed = append(observed, s)
+	}
+	return observed
+}
+
+func ExampleObserverFunc() {
+	service := core.NewService("example", "service")
+	service.Observers = []core.Observer{
+		core.NewLogObserver(core.DefaultLogger()),
+		core.ObserverFunc(func(e core.Event) {
+			if e.Type == core.EventWorkerErrored {
+				fmt.Printf("%s failed at %s: %v\n", e.Worker, e.Time, e.Err)
+			}
+		}),
+	}
+}
+
+func TestService_Run_observers(t *testing.T) {
+	failure := fmt.Errorf("failure")
+	obs := &observer{}
+	started := make(chan struct{})
+	service := &core.Service{
+		Name:        "test",
+		Type:        "service",
+		GracePeriod: time.Second,
+		Signals:     []os.Signal{syscall.SIGUSR2},
+		Observers:   []core.Observer{obs},
+	}
+	go func() {
+		<-started
+		raise(t, syscall.SIGUSR2)
+	}()
+	service.Run(context.Background(), &funcs{
+		run: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return failure
+		},
+		halt: func(context.Context) error { return nil },
+	})
+	observed := obs.observed()
+	test.Equals(t, 7, len(observed))
+	test.Equals(t, []string{
+		"service starting",
+		"worker started *core_test.funcs",
+		"signal received user defined signal 2",
+	}, observed[:3])
+	// Halt returns independently from Run, so only the order of stopping with an error is fixed.
+	halted := 5
+	if observed[3] == "worker halted *core_test.funcs" {
+		halted = 3
+	}
+	test.Equals(t, "worker halted *core_test.funcs", observed[halted])
+	observed = append(observed[:halted], observed[halted+1:]...)
+	test.Equals(t, []string{
+		"worker errored *core_test.funcs: failure",
+		"worker stopped *core_test.funcs: failure",
+		"service stopped",
+	}, observed[3:])
+	for _, e := range obs.events {
+		test.Equals(t, "test", e.Service)
+		test.Equals(t, false, e.Time.IsZero())
+	}
+}
+
+func TestService_Run_observingWorker(t *testing.T) {
+	w := &struct {
+		funcs
+		observer
+	}{funcs: funcs{
+		run:  func(context.Context) error { return nil },
+		halt: func(context.Context) error { return nil },
+	}}
+	service := &core.Service{Name: "test", Type: "service"}
+	service.Run(context.Background(), w)
+	test.Equals(t, "service starting", w.observed()[0])
+}
+
+func TestNewLogObserver(t *testing.T) {
+	buf := &bytes.Buffer{}
+	o := core.NewLogObserver(core.NewStdLogger(log.New(buf, "", 0)))
+	o.Observe(core.Event{Type: core.EventWorkerStarted, Worker: "server"})
+	o.Observe(core.Event{Type: core.EventWorkerErrored, Worker: "server", Err: fmt.Errorf("failure")})
+	test.Equals(t, []string{
+		`lifecycle event event="worker started" worker=server`,
+		`ERROR: lifecycle event event="worker errored" worker=server error=failure`,
+	}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
+
+func TestService_Run_observingServiceName(t *testing.T) {
+	obs := &observer{}
+	service := &core.Service{
+		Name:      "test",
+		Type:      "service",
+		Revision:  "94b8427c",
+		Version:   "v1.0.0",
+		Observers: []core.Observer{obs},
+	}
+	service.Run(context.Background(), &funcs{
+		run:  func(context.Context) error { return nil },
+		halt: func(context.Context) error { return nil },
+	})
+	for _, e := range obs.events {
+		test.Equals(t, "test", e.Service)
+	}
+}
@@ -18,4 +18,5 @@ require (
 	golang.org/x/text v0.3.2
 	google.golang.org/genproto v0.0.0-20190916214212-f660b8655731 // indirect
 	google.golang.org/grpc v1.24.0
+	gopkg.in/yaml.v2 v2.4.0
 )
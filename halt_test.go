@@ -0,0 +1,95 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+)
+
+type timed struct {
+	funcs
+	timeout time.Duration
+}
+
+func (t *timed) HaltTimeout() time.Duration {
+	return t.timeout
+}
+
+func TestService_Run_haltContext(t *testing.T) {
+	var (
+		haltErr     error
+		hasDeadline bool
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	res := service.RunWithResult(ctx, &funcs{
+		run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		halt: func(ctx context.Context) error {
+			haltErr = ctx.Err()
+			_, hasDeadline = ctx.Deadline()
+			return nil
+		},
+	})
+	test.Equals(t, core.ExitCodeOK, res.ExitCode())
+	test.Equals(t, nil, haltErr)
+	test.Equals(t, true, hasDeadline)
+	test.Equals(t, time.Second, res.Workers[0].Timeout)
+}
+
+func TestService_Run_haltTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+
+	deadlines := make(chan time.Time, 1)
+	slow := &timed{
+		timeout: 20 * time.Millisecond,
+		funcs: funcs{
+			run: func(context.Context) error {
+				cancel()
+				<-release
+				return nil
+			},
+			halt: func(ctx context.Context) error {
+				deadline, _ := ctx.Deadline()
+				deadlines <- deadline
+				return nil
+			},
+		},
+	}
+	rec := &recorder{}
+	server := newWorker("server", rec)
+
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Minute}
+	start := time.Now()
+	res := service.RunWithResult(ctx, server, core.DependsOn(slow, server))
+	test.Equals(t, core.ExitCodeTimeout, res.ExitCode())
+	test.Equals(t, true, time.Since(start) < time.Second)
+	test.Equals(t, true, (<-deadlines).Before(start.Add(time.Second)))
+	test.Equals(t, 20*time.Millisecond, res.Workers[1].Timeout)
+	test.Equals(t, false, res.Workers[1].Stopped)
+
+	// The server is still halted after the worker depending on it missed its deadline.
+	test.Equals(t, []string{"server run", "server halt"}, rec.recorded())
+	test.Equals(t, true, res.Workers[0].Stopped)
+}
+
+func TestWaitWithTimeout(t *testing.T) {
+	cancelled := make(chan int, 1)
+	completed, _, done := core.WaitWithTimeout(1, cancelled, time.Minute)
+	cancelled <- 1
+	done()
+	select {
+	case code := <-completed:
+		test.Equals(t, 0, code)
+	case <-time.After(time.Second):
+		t.Fatal("waited for the full timeout after all work had finished")
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Named represents the behaviour for a worker with a name to identify it by, in logs, events and results.
+type Named interface {
+	// Name should return a short, human readable name for the worker.
+	Name() string
+}
+
+// WorkerState represents where a worker is in its lifecycle.
+type WorkerState int
+
+const (
+	// WorkerPending is the state of a worker which has not been started yet.
+	WorkerPending WorkerState = iota
+	// WorkerRunning is the state of a worker which has been started, including while it is being restarted.
+	WorkerRunning
+	// WorkerHalting is the state of a worker which has been told to halt but has not stopped running yet.
+	WorkerHalting
+	// WorkerStopped is the state of a worker which has stopped running without an error.
+	WorkerStopped
+	// WorkerFailed is the state of a worker which has stopped running with an error.
+	WorkerFailed
+)
+
+func (s WorkerState) String() string {
+	switch s {
+	case WorkerPending:
+		return "pending"
+	case WorkerRunning:
+		return "running"
+	case WorkerHalting:
+		return "halting"
+	case WorkerStopped:
+		return "stopped"
+	case WorkerFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("state(%d)", int(s))
+	}
+}
+
+// MarshalText encodes the state as its name.
+func (s WorkerState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// WorkerInfo represents the state of a worker at a point in time.
+type WorkerInfo struct {
+	// Name is the identity of the worker.
+	Name string
+	// State is where the worker is in its lifecycle.
+	State WorkerState
+	// StartedAt is when the worker was last started, zero if it has not been started.
+	StartedAt time.Time
+	// Err is the last error the worker stopped running with, if any.
+	Err error
+}
+
+// Workers returns a snapshot of the state of every worker of the service, in the order they were given to the service.
+// It is safe to call while the service is running. After the service has stopped, it reports how each worker ended.
+func (s *Service) Workers() []WorkerInfo {
+	s.mu.Lock()
+	units := s.units
+	s.mu.Unlock()
+	infos := make([]WorkerInfo, len(units))
+	for i, u := range units {
+		infos[i] = u.info()
+	}
+	return infos
+}
+
+// info reports the current state of the worker, safe to call while the service is running.
+func (u *unit) info() WorkerInfo {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	info := WorkerInfo{
+		Name:      u.name,
+		StartedAt: u.startedAt,
+		Err:       u.lastErr,
+	}
+	switch {
+	case !u.started():
+		info.State = WorkerPending
+	case u.stopped() && u.err != nil:
+		info.State = WorkerFailed
+		info.Err = u.err
+	case u.stopped():
+		info.State = WorkerStopped
+	case u.halting:
+		info.State = WorkerHalting
+	default:
+		info.State = WorkerRunning
+	}
+	return info
+}
@@ -0,0 +1,70 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+)
+
+type named struct {
+	funcs
+	name string
+}
+
+func (n *named) Name() string {
+	return n.name
+}
+
+func TestService_Workers(t *testing.T) {
+	failure := fmt.Errorf("failure")
+	var (
+		running  = make(chan struct{})
+		release  = make(chan struct{})
+		snapshot []core.WorkerInfo
+	)
+	consumer := &named{name: "consumer", funcs: funcs{
+		run: func(ctx context.Context) error {
+			close(running)
+			<-release
+			return failure
+		},
+		halt: func(context.Context) error { return nil },
+	}}
+	rec := &recorder{}
+	server := newWorker("server", rec)
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	test.Equals(t, 0, len(service.Workers()))
+
+	go func() {
+		<-running
+		snapshot = service.Workers()
+		close(release)
+	}()
+	res := service.RunWithResult(context.Background(), server, core.DependsOn(consumer, server))
+
+	test.Equals(t, "*core_test.worker", snapshot[0].Name)
+	test.Equals(t, core.WorkerRunning, snapshot[0].State)
+	test.Equals(t, false, snapshot[0].StartedAt.IsZero())
+	test.Equals(t, "consumer", snapshot[1].Name)
+	test.Equals(t, core.WorkerRunning, snapshot[1].State)
+
+	after := service.Workers()
+	test.Equals(t, core.WorkerStopped, after[0].State)
+	test.Equals(t, core.WorkerFailed, after[1].State)
+	test.Equals(t, failure, after[1].Err)
+	test.Equals(t, "consumer", res.Failed()[0].Name)
+}
+
+func TestService_Workers_notStarted(t *testing.T) {
+	missing := newWorker("missing", &recorder{})
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	res := service.RunWithResult(context.Background(), core.DependsOn(newWorker("server", &recorder{}), missing))
+	test.Equals(t, core.ExitCodeFailure, res.ExitCode())
+	workers := service.Workers()
+	test.Equals(t, core.WorkerPending, workers[0].State)
+	test.Equals(t, "pending", workers[0].State.String())
+}
@@ -0,0 +1,245 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level represents the severity of a log record.
+type Level int
+
+const (
+	// LevelDebug is used for records which are only useful when debugging.
+	LevelDebug Level = iota
+	// LevelInfo is used for records about the normal operation of the service.
+	LevelInfo
+	// LevelWarn is used for records about something unexpected which the service can recover from.
+	LevelWarn
+	// LevelError is used for records about failures.
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// Logger represents the behaviour for leveled and structured logging.
+// Fields are provided as alternating keys and values, eg. logger.Info("serving http", "addr", addr).
+type Logger interface {
+	// Debug logs a record at debug level.
+	Debug(msg string, keyvals ...interface{})
+	// Info logs a record at info level.
+	Info(msg string, keyvals ...interface{})
+	// Warn logs a record at warning level.
+	Warn(msg string, keyvals ...interface{})
+	// Error logs a record at error level.
+	Error(msg string, keyvals ...interface{})
+	// With returns a logger which adds the fields to every record.
+	With(keyvals ...interface{}) Logger
+}
+
+// Loggable represents the behaviour for a worker which accepts the logger of the service running it.
+type Loggable interface {
+	// SetLogger should make the worker log through the given logger.
+	SetLogger(Logger)
+}
+
+var (
+	defaultLoggerMu sync.RWMutex
+	defaultLogger   = NewStdLogger(nil)
+)
+
+// DefaultLogger returns the package level logger, used whenever no other logger has been provided.
+func DefaultLogger() Logger {
+	defaultLoggerMu.RLock()
+	defer defaultLoggerMu.RUnlock()
+	return defaultLogger
+}
+
+// SetDefaultLogger replaces the package level logger.
+func SetDefaultLogger(logger Logger) {
+	if logger == nil {
+		logger = NewStdLogger(nil)
+	}
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger = logger
+}
+
+// NewStdLogger returns a logger writing plain text lines through a logger from the standard library.
+// When no logger is provided the standard logger of the log package is used.
+// Records are written as the message followed by the fields formatted as key=value pairs.
+func NewStdLogger(logger *log.Logger) Logger {
+	return &stdLogger{out: logger}
+}
+
+type stdLogger struct {
+	out    *log.Logger
+	fields []interface{}
+}
+
+// Debug logs a record at debug level.
+func (l *stdLogger) Debug(msg string, keyvals ...interface{}) { l.print(LevelDebug, msg, keyvals) }
+
+// Info logs a record at info level.
+func (l *stdLogger) Info(msg string, keyvals ...interface{}) { l.print(LevelInfo, msg, keyvals) }
+
+// Warn logs a record at warning level.
+func (l *stdLogger) Warn(msg string, keyvals ...interface{}) { l.print(LevelWarn, msg, keyvals) }
+
+// Error logs a record at error level.
+func (l *stdLogger) Error(msg string, keyvals ...interface{}) { l.print(LevelError, msg, keyvals) }
+
+// With returns a logger which adds the fields to every record.
+func (l *stdLogger) With(keyvals ...interface{}) Logger {
+	return &stdLogger{
+		out:    l.out,
+		fields: append(append([]interface{}{}, l.fields...), keyvals...),
+	}
+}
+
+func (l *stdLogger) print(level Level, msg string, keyvals []interface{}) {
+	// The call depth points at the caller of the leveled method, for use with log.Lshortfile.
+	const calldepth = 3
+	var w strings.Builder
+	if level != LevelInfo {
+		w.WriteString(strings.ToUpper(level.String()) + ": ")
+	}
+	w.WriteString(msg)
+	eachField(merge(keyvals, l.fields), func(key string, value interface{}) {
+		w.WriteString(" " + key + "=" + textValue(value))
+	})
+	if l.out == nil {
+		log.Output(calldepth, w.String())
+		return
+	}
+	l.out.Output(calldepth, w.String())
+}
+
+func textValue(value interface{}) string {
+	s := fmt.Sprint(value)
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// NewJSONLogger returns a logger writing every record as a single line JSON object.
+// Each object contains the time, level and message of the record followed by its fields.
+func NewJSONLogger(w io.Writer) Logger {
+	return &jsonLogger{
+		out: &syncWriter{w: w},
+		now: time.Now,
+	}
+}
+
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+type jsonLogger struct {
+	out    io.Writer
+	now    func() time.Time
+	fields []interface{}
+}
+
+// Debug logs a record at debug level.
+func (l *jsonLogger) Debug(msg string, keyvals ...interface{}) { l.print(LevelDebug, msg, keyvals) }
+
+// Info logs a record at info level.
+func (l *jsonLogger) Info(msg string, keyvals ...interface{}) { l.print(LevelInfo, msg, keyvals) }
+
+// Warn logs a record at warning level.
+func (l *jsonLogger) Warn(msg string, keyvals ...interface{}) { l.print(LevelWarn, msg, keyvals) }
+
+// Error logs a record at error level.
+func (l *jsonLogger) Error(msg string, keyvals ...interface{}) { l.print(LevelError, msg, keyvals) }
+
+// With returns a logger which adds the fields to every record.
+func (l *jsonLogger) With(keyvals ...interface{}) Logger {
+	return &jsonLogger{
+		out:    l.out,
+		now:    l.now,
+		fields: append(append([]interface{}{}, l.fields...), keyvals...),
+	}
+}
+
+func (l *jsonLogger) print(level Level, msg string, keyvals []interface{}) {
+	var w strings.Builder
+	w.WriteString(`{"time":`)
+	w.Write(jsonValue(l.now().UTC().Format(time.RFC3339Nano)))
+	w.WriteString(`,"level":`)
+	w.Write(jsonValue(level.String()))
+	w.WriteString(`,"msg":`)
+	w.Write(jsonValue(msg))
+	eachField(merge(keyvals, l.fields), func(key string, value interface{}) {
+		w.WriteString(",")
+		w.Write(jsonValue(key))
+		w.WriteString(":")
+		w.Write(jsonValue(value))
+	})
+	w.WriteString("}\n")
+	io.WriteString(l.out, w.String())
+}
+
+func jsonValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case error:
+		value = v.Error()
+	case time.Duration:
+		value = v.String()
+	case fmt.Stringer:
+		value = v.String()
+	}
+	bts, err := json.Marshal(value)
+	if err != nil {
+		bts, _ = json.Marshal(fmt.Sprint(value))
+	}
+	return bts
+}
+
+// merge appends the fields of a logger to the fields of a record, without modifying either.
+func merge(keyvals, fields []interface{}) []interface{} {
+	merged := make([]interface{}, 0, len(keyvals)+len(fields)+1)
+	merged = append(merged, keyvals...)
+	if len(keyvals)%2 == 1 {
+		merged = append(merged, "(MISSING)")
+	}
+	return append(merged, fields...)
+}
+
+// eachField calls fn for every key and value pair, marking a key without a value as missing.
+func eachField(keyvals []interface{}, fn func(key string, value interface{})) {
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		if i+1 >= len(keyvals) {
+			fn(key, "(MISSING)")
+			return
+		}
+		fn(key, keyvals[i+1])
+	}
+}
@@ -0,0 +1,99 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+)
+
+type loggable struct {
+	funcs
+	logger core.Logger
+}
+
+func (l *loggable) SetLogger(logger core.Logger) {
+	l.logger = logger
+}
+
+func ExampleNewJSONLogger() {
+	service := core.NewService("example", "service")
+	service.Logger = core.NewJSONLogger(os.Stdout)
+}
+
+func TestNewStdLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := core.NewStdLogger(log.New(buf, "", 0)).With("service", "example")
+
+	logger.Info("serving http", "addr", "http://0.0.0.0:80")
+	logger.Warn("check failed", "message", "not ready")
+	logger.Error("service errored", "error", fmt.Errorf("failure"), "odd")
+
+	test.Equals(t, []string{
+		`serving http addr=http://0.0.0.0:80 service=example`,
+		`WARN: check failed message="not ready" service=example`,
+		`ERROR: service errored error=failure odd=(MISSING) service=example`,
+	}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
+
+func TestNewJSONLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := core.NewJSONLogger(buf).With("service", "example")
+
+	logger.Debug("restarting", "delay", time.Second, "error", fmt.Errorf("failure"), "attempt", 2)
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	test.Equals(t, "debug", record["level"])
+	test.Equals(t, "restarting", record["msg"])
+	test.Equals(t, "1s", record["delay"])
+	test.Equals(t, "failure", record["error"])
+	test.Equals(t, float64(2), record["attempt"])
+	test.Equals(t, "example", record["service"])
+	if _, err := time.Parse(time.RFC3339Nano, record["time"].(string)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_Run_logger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := &loggable{funcs: funcs{
+		run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		halt: func(context.Context) error { return nil },
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service := &core.Service{
+		Name:     "test",
+		Type:     "service",
+		Version:  "1.0.0",
+		Revision: "abcdef",
+		Logger:   core.NewJSONLogger(buf),
+	}
+	service.Run(ctx, w)
+
+	w.logger.Info("hello")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &record); err != nil {
+		t.Fatal(err)
+	}
+	test.Equals(t, "hello", record["msg"])
+	test.Equals(t, "test", record["service"])
+	test.Equals(t, "1.0.0", record["version"])
+	test.Equals(t, "abcdef", record["revision"])
+}
@@ -6,5 +6,5 @@ import "log"
 // DEPRECATED: Import github.com/LUSHDigital/core-lush/lushlog as a side-effect.
 func SetupLogs() {
 	log.SetFlags(log.Lshortfile | log.LstdFlags)
-	log.Println("DEPRECATED: import github.com/LUSHDigital/core-lush/lushlog as a side-effect.")
+	DefaultLogger().Warn("DEPRECATED: import github.com/LUSHDigital/core-lush/lushlog as a side-effect.")
 }
@@ -0,0 +1,2 @@
+// Package authmw provides transport middlewares for authenticating api consumers with bearer tokens.
+package authmw
@@ -0,0 +1,154 @@
+package authmw
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dgrijalva/jwt-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/middleware"
+)
+
+const (
+	metaAuthorizationKey = "authorization"
+)
+
+var (
+	// ErrMissingToken happens when a call requiring a token does not have a bearer token in its metadata.
+	ErrMissingToken = status.Error(codes.Unauthenticated, "missing bearer token")
+
+	// ErrInvalidToken happens when the bearer token of a call is malformed or cannot be validated.
+	ErrInvalidToken = status.Error(codes.Unauthenticated, "invalid bearer token")
+
+	// ErrTokenExpired happens when the bearer token of a call has expired.
+	ErrTokenExpired = status.Error(codes.Unauthenticated, "bearer token has expired")
+
+	// ErrInsufficientGrants happens when the bearer token of a call does not have the grants required by the method.
+	ErrInsufficientGrants = status.Error(codes.PermissionDenied, "bearer token does not have the required grants")
+)
+
+// MethodMatcher returns the grants required to call a method by its full name, eg. "/orders.Orders/List",
+// and whether the method can only be called with a token at all.
+type MethodMatcher func(fullMethod string) (grants []string, required bool)
+
+// GrantsByMethod returns a method matcher requiring the grants in the map for each method.
+// Every method requires a token, including the methods which are not in the map.
+func GrantsByMethod(methods map[string][]string) MethodMatcher {
+	return func(fullMethod string) ([]string, bool) {
+		return methods[fullMethod], true
+	}
+}
+
+// UnaryServerInterceptor is a gRPC server-side unary interceptor that validates the bearer token
+// in the authorization metadata and attaches its claims to the context.
+func (a *Authenticator) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.interceptServer(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor is a gRPC server-side streaming interceptor that validates the bearer token
+// in the authorization metadata and attaches its claims to the context.
+func (a *Authenticator) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.interceptServer(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	wrapped := middleware.WrapServerStream(ss)
+	wrapped.WrappedContext = ctx
+	return handler(srv, wrapped)
+}
+
+// interceptServer validates the bearer token of a call against the requirements of the method.
+func (a *Authenticator) interceptServer(ctx context.Context, fullMethod string) (context.Context, error) {
+	grants, required := []string(nil), true
+	if a.Methods != nil {
+		grants, required = a.Methods(fullMethod)
+	}
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(metaAuthorizationKey); len(values) > 0 {
+			header = values[0]
+		}
+	}
+	if header == "" {
+		if required || len(grants) > 0 {
+			return ctx, ErrMissingToken
+		}
+		return ctx, nil
+	}
+	raw, ok := BearerToken(header)
+	if !ok {
+		return ctx, ErrInvalidToken
+	}
+	var claims auth.Claims
+	if err := a.Parser.Parse(raw, &claims); err != nil {
+		if errors.Is(err, auth.ErrTokenExpired) {
+			return ctx, ErrTokenExpired
+		}
+		return ctx, ErrInvalidToken
+	}
+	if !claims.HasAllGrants(grants...) {
+		return ctx, ErrInsufficientGrants
+	}
+	return auth.ContextWithClaims(ctx, claims), nil
+}
+
+// TokenSource represents a source of bearer tokens for outgoing calls.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc allows a function to be used as a token source.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token returns a bearer token by calling the function.
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// Issuer represents an issuer of tokens, eg. an auth.Issuer or auth.RotatingIssuer.
+type Issuer interface {
+	Issue(claims jwt.Claims) (string, error)
+}
+
+// IssuerTokenSource returns a token source issuing a new token for every call, with the claims returned by the function.
+func IssuerTokenSource(issuer Issuer, claims func() jwt.Claims) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		return issuer.Issue(claims())
+	})
+}
+
+// AppendTokenToOutgoingContext returns a new context with the bearer token in the authorization metadata of outgoing calls.
+func AppendTokenToOutgoingContext(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, metaAuthorizationKey, bearerScheme+" "+token)
+}
+
+// UnaryClientInterceptor returns a gRPC client-side unary interceptor that attaches a bearer token from the source to every call.
+func UnaryClientInterceptor(source TokenSource) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		token, err := source.Token(ctx)
+		if err != nil {
+			return status.Errorf(codes.Unauthenticated, "cannot get bearer token: %v", err)
+		}
+		return invoker(AppendTokenToOutgoingContext(ctx, token), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a gRPC client-side streaming interceptor that attaches a bearer token from the source to every stream.
+func StreamClientInterceptor(source TokenSource) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		token, err := source.Token(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "cannot get bearer token: %v", err)
+		}
+		return streamer(AppendTokenToOutgoingContext(ctx, token), desc, cc, method, opts...)
+	}
+}
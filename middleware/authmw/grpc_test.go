@@ -0,0 +1,161 @@
+package authmw_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/middleware/authmw"
+	"github.com/LUSHDigital/core/middleware/internal/greeter"
+	"github.com/LUSHDigital/core/test"
+)
+
+const sayHello = "/Greeter/SayHello"
+
+type GreeterServer struct{}
+
+func (*GreeterServer) SayHello(ctx context.Context, _ *greeter.Empty) (*greeter.Empty, error) {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok || claims.Consumer.ID != "1234" {
+		return nil, fmt.Errorf("failed to intercept claims")
+	}
+	return &greeter.Empty{}, nil
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
+
+func ExampleAuthenticator_UnaryServerInterceptor() {
+	authenticator := authmw.NewAuthenticator(parser)
+	authenticator.Methods = authmw.GrantsByMethod(map[string][]string{
+		"/orders.Orders/List": {"orders.read"},
+	})
+	grpc.NewServer(
+		grpc.UnaryInterceptor(authenticator.UnaryServerInterceptor),
+		grpc.StreamInterceptor(authenticator.StreamServerInterceptor),
+	)
+}
+
+func ExampleUnaryClientInterceptor() {
+	source := authmw.IssuerTokenSource(issuer, func() jwt.Claims {
+		return &auth.Claims{
+			StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Minute).Unix()},
+			Consumer:       auth.Consumer{ID: "orders-service"},
+		}
+	})
+	grpc.Dial("localhost:50051",
+		grpc.WithUnaryInterceptor(authmw.UnaryClientInterceptor(source)),
+		grpc.WithStreamInterceptor(authmw.StreamClientInterceptor(source)),
+	)
+}
+
+func TestAuthenticator_interceptors(t *testing.T) {
+	var (
+		valid   = issue(t, time.Now().Add(time.Hour), "orders.read")
+		expired = issue(t, time.Now().Add(-time.Hour), "orders.read")
+	)
+	authenticator := authmw.NewAuthenticator(parser)
+	authenticator.Methods = func(fullMethod string) ([]string, bool) {
+		switch fullMethod {
+		case "/orders.Orders/List":
+			return []string{"orders.read"}, true
+		case "/orders.Orders/Delete":
+			return []string{"orders.delete"}, true
+		case "/orders.Orders/Ping":
+			return nil, false
+		default:
+			return nil, true
+		}
+	}
+	cases := []struct {
+		name   string
+		method string
+		header string
+		err    error
+		claims bool
+	}{
+		{name: "granted", method: "/orders.Orders/List", header: "Bearer " + valid, claims: true},
+		{name: "token only", method: "/orders.Orders/Get", header: "Bearer " + valid, claims: true},
+		{name: "not granted", method: "/orders.Orders/Delete", header: "Bearer " + valid, err: authmw.ErrInsufficientGrants},
+		{name: "missing token", method: "/orders.Orders/Get", err: authmw.ErrMissingToken},
+		{name: "expired token", method: "/orders.Orders/Get", header: "Bearer " + expired, err: authmw.ErrTokenExpired},
+		{name: "invalid token", method: "/orders.Orders/Get", header: "Bearer abc.def.ghi", err: authmw.ErrInvalidToken},
+		{name: "other scheme", method: "/orders.Orders/Get", header: "Basic abc", err: authmw.ErrInvalidToken},
+		{name: "optional without token", method: "/orders.Orders/Ping"},
+		{name: "optional with token", method: "/orders.Orders/Ping", header: "Bearer " + valid, claims: true},
+		{name: "optional with expired token", method: "/orders.Orders/Ping", header: "Bearer " + expired, err: authmw.ErrTokenExpired},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			md := metadata.MD{}
+			if c.header != "" {
+				md.Set("authorization", c.header)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			var unaryClaims bool
+			_, err := authenticator.UnaryServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: c.method}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				_, unaryClaims = auth.ClaimsFromContext(ctx)
+				return nil, nil
+			})
+			test.Equals(t, c.err, err)
+			test.Equals(t, c.claims, unaryClaims)
+
+			var streamClaims bool
+			err = authenticator.StreamServerInterceptor(nil, &serverStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: c.method}, func(_ interface{}, ss grpc.ServerStream) error {
+				_, streamClaims = auth.ClaimsFromContext(ss.Context())
+				return nil
+			})
+			test.Equals(t, c.err, err)
+			test.Equals(t, c.claims, streamClaims)
+		})
+	}
+}
+
+func TestGRPCInterceptors(t *testing.T) {
+	authenticator := authmw.NewAuthenticator(parser)
+	authenticator.Methods = authmw.GrantsByMethod(map[string][]string{
+		sayHello: {"greeter.hello"},
+	})
+	server := grpc.NewServer(grpc.UnaryInterceptor(authenticator.UnaryServerInterceptor))
+	greeter.RegisterGreeterServer(server, &GreeterServer{})
+	listener, err := net.Listen("tcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(listener)
+	defer server.Stop()
+
+	dial := func(grants ...string) greeter.GreeterClient {
+		source := authmw.IssuerTokenSource(issuer, func() jwt.Claims {
+			return &auth.Claims{
+				StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Minute).Unix()},
+				Consumer:       auth.Consumer{ID: "1234", Grants: grants},
+			}
+		})
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure(), grpc.WithUnaryInterceptor(authmw.UnaryClientInterceptor(source)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return greeter.NewGreeterClient(conn)
+	}
+
+	_, err = dial("greeter.hello").SayHello(context.Background(), &greeter.Empty{})
+	test.Equals(t, nil, err)
+	_, err = dial().SayHello(context.Background(), &greeter.Empty{})
+	test.Equals(t, authmw.ErrInsufficientGrants.Error(), err.Error())
+}
@@ -0,0 +1,139 @@
+package authmw
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/rest"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	authenticateHeader  = "WWW-Authenticate"
+	bearerScheme        = "Bearer"
+
+	errInvalidRequest    = "invalid_request"
+	errInvalidToken      = "invalid_token"
+	errInsufficientScope = "insufficient_scope"
+
+	descriptionMalformed = "the authorization header must use the bearer scheme"
+	descriptionInvalid   = "the token is invalid"
+	descriptionExpired   = "the token has expired"
+	descriptionNoGrant   = "the token does not have the required grants"
+)
+
+// NewAuthenticator returns an authenticator validating bearer tokens with the parser.
+func NewAuthenticator(parser *auth.Parser) *Authenticator {
+	return &Authenticator{Parser: parser}
+}
+
+// Authenticator provides HTTP middlewares and gRPC interceptors for authenticating requests with a bearer token.
+// The claims of a valid token are put in the request context, to be taken out with auth.ClaimsFromContext.
+type Authenticator struct {
+	// Parser is used to validate the bearer tokens.
+	Parser *auth.Parser
+	// Realm is the protection space included in the WWW-Authenticate header, if any.
+	Realm string
+	// Methods returns the requirements of each gRPC method, where every method requires a token when nil.
+	Methods MethodMatcher
+}
+
+// Required will only serve requests with a valid bearer token, responding with 401 Unauthorized otherwise.
+func (a *Authenticator) Required(next http.Handler) http.Handler {
+	return a.RequireGrants()(next)
+}
+
+// Optional will serve requests without a bearer token, but responds with 401 Unauthorized when the token is invalid.
+func (a *Authenticator) Optional(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(authorizationHeader) == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		claims, ok := a.authenticate(w, r)
+		if !ok {
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.ContextWithClaims(r.Context(), claims)))
+	})
+}
+
+// RequireGrants returns a middleware which will only serve requests with a valid bearer token,
+// which has been given every one of the grants, responding with 403 Forbidden when it has not.
+func (a *Authenticator) RequireGrants(grants ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := a.authenticate(w, r)
+			if !ok {
+				return
+			}
+			if !claims.HasAllGrants(grants...) {
+				w.Header().Set(authenticateHeader, a.challenge(errInsufficientScope, descriptionNoGrant, grants))
+				rest.ForbiddenError().WriteTo(w)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.ContextWithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// authenticate validates the bearer token of a request, writing an unauthorized response when it is missing or invalid.
+func (a *Authenticator) authenticate(w http.ResponseWriter, r *http.Request) (auth.Claims, bool) {
+	var claims auth.Claims
+	header := r.Header.Get(authorizationHeader)
+	if header == "" {
+		w.Header().Set(authenticateHeader, a.challenge("", "", nil))
+		rest.UnauthorizedError().WriteTo(w)
+		return claims, false
+	}
+	raw, ok := BearerToken(header)
+	if !ok {
+		w.Header().Set(authenticateHeader, a.challenge(errInvalidRequest, descriptionMalformed, nil))
+		rest.UnauthorizedError().WriteTo(w)
+		return claims, false
+	}
+	if err := a.Parser.Parse(raw, &claims); err != nil {
+		description := descriptionInvalid
+		if errors.Is(err, auth.ErrTokenExpired) {
+			description = descriptionExpired
+		}
+		w.Header().Set(authenticateHeader, a.challenge(errInvalidToken, description, nil))
+		rest.UnauthorizedError().WriteTo(w)
+		return claims, false
+	}
+	return claims, true
+}
+
+// challenge builds the value of the WWW-Authenticate header for the bearer scheme (RFC 6750).
+func (a *Authenticator) challenge(code, description string, scope []string) string {
+	var params []string
+	if a.Realm != "" {
+		params = append(params, fmt.Sprintf("realm=%q", a.Realm))
+	}
+	if code != "" {
+		params = append(params, fmt.Sprintf("error=%q", code))
+	}
+	if description != "" {
+		params = append(params, fmt.Sprintf("error_description=%q", description))
+	}
+	if len(scope) > 0 {
+		params = append(params, fmt.Sprintf("scope=%q", strings.Join(scope, " ")))
+	}
+	if len(params) == 0 {
+		return bearerScheme
+	}
+	return bearerScheme + " " + strings.Join(params, ", ")
+}
+
+// BearerToken takes the value of an Authorization header and returns the token when it uses the bearer scheme.
+func BearerToken(header string) (string, bool) {
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], bearerScheme) {
+		return "", false
+	}
+	token := strings.TrimSpace(parts[1])
+	return token, token != ""
+}
@@ -0,0 +1,190 @@
+package authmw_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/auth/authmock"
+	"github.com/LUSHDigital/core/middleware/authmw"
+	"github.com/LUSHDigital/core/test"
+)
+
+var (
+	issuer, parser = authmock.MustNewRSAIsserAndParser()
+)
+
+func ExampleAuthenticator_RequireGrants() {
+	authenticator := authmw.NewAuthenticator(parser)
+	handler := authenticator.RequireGrants("orders.read")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := auth.ClaimsFromContext(r.Context())
+		fmt.Fprintln(w, claims.Consumer.ID)
+	}))
+	http.Handle("/orders", handler)
+}
+
+func issue(t *testing.T, expires time.Time, grants ...string) string {
+	t.Helper()
+	raw, err := issuer.Issue(&auth.Claims{
+		StandardClaims: jwt.StandardClaims{ExpiresAt: expires.Unix()},
+		Consumer: auth.Consumer{
+			ID:     "1234",
+			Grants: grants,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestAuthenticator(t *testing.T) {
+	refreshToken, err := auth.NewRefresher(issuer, parser, auth.NewMemoryRevocation(), time.Hour).Issue("1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		valid   = "Bearer " + issue(t, time.Now().Add(time.Hour), "orders.read")
+		expired = "Bearer " + issue(t, time.Now().Add(-time.Hour), "orders.read")
+		refresh = "Bearer " + refreshToken
+	)
+	authenticator := authmw.NewAuthenticator(parser)
+	authenticator.Realm = "orders"
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := auth.ClaimsFromContext(r.Context())
+		if ok {
+			fmt.Fprint(w, claims.Consumer.ID)
+		}
+	})
+	cases := []struct {
+		name          string
+		middleware    func(http.Handler) http.Handler
+		authorization string
+		code          int
+		body          string
+		authenticate  string
+	}{
+		{
+			name:          "required with valid token",
+			middleware:    authenticator.Required,
+			authorization: valid,
+			code:          http.StatusOK,
+			body:          "1234",
+		},
+		{
+			name:         "required without token",
+			middleware:   authenticator.Required,
+			code:         http.StatusUnauthorized,
+			body:         `{"code":401,"message":"unauthorized"}`,
+			authenticate: `Bearer realm="orders"`,
+		},
+		{
+			name:          "required with other scheme",
+			middleware:    authenticator.Required,
+			authorization: "Basic dXNlcjpwYXNz",
+			code:          http.StatusUnauthorized,
+			body:          `{"code":401,"message":"unauthorized"}`,
+			authenticate:  `Bearer realm="orders", error="invalid_request", error_description="the authorization header must use the bearer scheme"`,
+		},
+		{
+			name:          "required with expired token",
+			middleware:    authenticator.Required,
+			authorization: expired,
+			code:          http.StatusUnauthorized,
+			body:          `{"code":401,"message":"unauthorized"}`,
+			authenticate:  `Bearer realm="orders", error="invalid_token", error_description="the token has expired"`,
+		},
+		{
+			name:          "required with invalid token",
+			middleware:    authenticator.Required,
+			authorization: "bearer abc.def.ghi",
+			code:          http.StatusUnauthorized,
+			body:          `{"code":401,"message":"unauthorized"}`,
+			authenticate:  `Bearer realm="orders", error="invalid_token", error_description="the token is invalid"`,
+		},
+		{
+			name:          "required with refresh token",
+			middleware:    authenticator.Required,
+			authorization: refresh,
+			code:          http.StatusUnauthorized,
+			body:          `{"code":401,"message":"unauthorized"}`,
+			authenticate:  `Bearer realm="orders", error="invalid_token", error_description="the token is invalid"`,
+		},
+		{
+			name:       "optional without token",
+			middleware: authenticator.Optional,
+			code:       http.StatusOK,
+			body:       "",
+		},
+		{
+			name:          "optional with valid token",
+			middleware:    authenticator.Optional,
+			authorization: valid,
+			code:          http.StatusOK,
+			body:          "1234",
+		},
+		{
+			name:          "optional with expired token",
+			middleware:    authenticator.Optional,
+			authorization: expired,
+			code:          http.StatusUnauthorized,
+			body:          `{"code":401,"message":"unauthorized"}`,
+			authenticate:  `Bearer realm="orders", error="invalid_token", error_description="the token has expired"`,
+		},
+		{
+			name:          "granted",
+			middleware:    authenticator.RequireGrants("orders.read"),
+			authorization: valid,
+			code:          http.StatusOK,
+			body:          "1234",
+		},
+		{
+			name:          "not granted",
+			middleware:    authenticator.RequireGrants("orders.read", "orders.write"),
+			authorization: valid,
+			code:          http.StatusForbidden,
+			body:          `{"code":403,"message":"forbidden"}`,
+			authenticate:  `Bearer realm="orders", error="insufficient_scope", error_description="the token does not have the required grants", scope="orders.read orders.write"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			if c.authorization != "" {
+				r.Header.Set("Authorization", c.authorization)
+			}
+			w := httptest.NewRecorder()
+			c.middleware(next).ServeHTTP(w, r)
+			test.Equals(t, c.code, w.Code)
+			test.Equals(t, c.body, strings.TrimSpace(w.Body.String()))
+			test.Equals(t, c.authenticate, w.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	cases := []struct {
+		header string
+		token  string
+		ok     bool
+	}{
+		{header: "Bearer abc", token: "abc", ok: true},
+		{header: "bearer  abc ", token: "abc", ok: true},
+		{header: "Bearer", ok: false},
+		{header: "Bearer ", ok: false},
+		{header: "Basic abc", ok: false},
+	}
+	for _, c := range cases {
+		t.Run(c.header, func(t *testing.T) {
+			token, ok := authmw.BearerToken(c.header)
+			test.Equals(t, c.token, token)
+			test.Equals(t, c.ok, ok)
+		})
+	}
+}
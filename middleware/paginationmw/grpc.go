@@ -2,9 +2,9 @@ package paginationmw
 
 import (
 	"context"
-	"log"
 	"strconv"
 
+	"github.com/LUSHDigital/core"
 	"github.com/LUSHDigital/core/pagination"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
@@ -26,14 +26,14 @@ func InterceptServerRequest(ctx context.Context) (pagination.Request, error) {
 		val := md.Get(key)
 		if len(val) < 1 {
 			if Debug {
-				log.Printf("grpc pagination: tried to access %q meta data key but it didn't have any values", key)
+				core.DefaultLogger().Debug("grpc pagination: tried to access meta data key but it didn't have any values", "key", key)
 			}
 			return 0, nil
 		}
 		n, err := strconv.ParseUint(val[0], 10, 64)
 		if err != nil {
 			if Debug {
-				log.Printf("grpc pagination: could not parse %q key: %v", key, err)
+				core.DefaultLogger().Debug("grpc pagination: could not parse key", "key", key, "error", err)
 			}
 			return 0, pagination.ErrMetadataInvalid(key, err)
 		}
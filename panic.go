@@ -0,0 +1,43 @@
+package core
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrPanic is the error for a worker which panicked while running or halting.
+type ErrPanic struct {
+	Worker string
+	Value  interface{}
+	Stack  []byte
+}
+
+func (e ErrPanic) Error() string {
+	return fmt.Sprintf("%s panicked: %v", e.Worker, e.Value)
+}
+
+// Unwrap returns the value the worker panicked with, if it is an error.
+func (e ErrPanic) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// PanicHandler is called with every panic recovered from a worker, eg. to forward it to error reporting.
+type PanicHandler func(ErrPanic)
+
+// protect calls fn and turns any panic into an error carrying the stack trace.
+func (u *unit) protect(fn func() error) (err error) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		p := ErrPanic{Worker: u.name, Value: v, Stack: debug.Stack()}
+		u.log.Error("service panicked", "panic", v, "stack", string(p.Stack))
+		if u.onPanic != nil {
+			u.onPanic(p)
+		}
+		err = p
+	}()
+	return fn()
+}
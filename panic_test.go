@@ -0,0 +1,83 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+)
+
+func ExamplePanicHandler() {
+	service := core.NewService("example", "service")
+	service.PanicHandler = func(p core.ErrPanic) {
+		fmt.Fprintf(os.Stderr, "%v\n%s", p, p.Stack)
+	}
+	service.MustRun(ctx, &funcs{
+		run:  func(context.Context) error { panic("oops") },
+		halt: func(context.Context) error { return nil },
+	})
+}
+
+func TestService_Run_panic(t *testing.T) {
+	sink, err := ioutil.TempFile("", "panics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(sink.Name())
+	defer sink.Close()
+
+	rec := &recorder{}
+	server := newWorker("server", rec)
+	service := &core.Service{
+		Name:        "test",
+		Type:        "service",
+		GracePeriod: time.Second,
+		PanicHandler: func(p core.ErrPanic) {
+			fmt.Fprintf(sink, "%v\n%s", p, p.Stack)
+		},
+	}
+	res := service.RunWithResult(context.Background(),
+		server,
+		core.DependsOn(&funcs{
+			run:  func(context.Context) error { panic("oops") },
+			halt: func(context.Context) error { return nil },
+		}, server),
+	)
+	test.Equals(t, core.ExitCodeFailure, res.ExitCode())
+	test.Equals(t, []string{"server run", "server halt"}, rec.recorded())
+
+	p, ok := res.Workers[1].RunErr.(core.ErrPanic)
+	test.Equals(t, true, ok)
+	test.Equals(t, "*core_test.funcs panicked: oops", p.Error())
+	test.Equals(t, true, strings.Contains(string(p.Stack), "panic_test.go"))
+
+	reported, err := ioutil.ReadFile(sink.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Equals(t, true, strings.HasPrefix(string(reported), "*core_test.funcs panicked: oops\n"))
+}
+
+func TestService_Run_panicHalting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	failure := fmt.Errorf("failure")
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	res := service.RunWithResult(ctx, &funcs{
+		run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		halt: func(context.Context) error { panic(failure) },
+	})
+	test.Equals(t, core.ExitCodeHaltFailure, res.ExitCode())
+	p, ok := res.Workers[0].HaltErr.(core.ErrPanic)
+	test.Equals(t, true, ok)
+	test.Equals(t, failure, p.Unwrap())
+}
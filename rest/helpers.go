@@ -78,6 +78,11 @@ func UnauthorizedError() *Response {
 	return &Response{Code: http.StatusUnauthorized, Message: "unauthorized"}
 }
 
+// ForbiddenError returns a prepared 403 Forbidden error.
+func ForbiddenError() *Response {
+	return &Response{Code: http.StatusForbidden, Message: "forbidden"}
+}
+
 // !!! DEPRECATED FUNCTIONS !!!
 
 // NotFoundErr returns a prepared 404 Not Found response, including the message passed by the user in the message field of the response object.
@@ -159,3 +159,14 @@ func TestUnauthorizedError(t *testing.T) {
 	json.Unmarshal(req.Body.Bytes(), e)
 	test.Equals(t, "unauthorized", e.Message)
 }
+
+func TestForbiddenError(t *testing.T) {
+	req := httptest.NewRecorder()
+	err := rest.ForbiddenError().WriteTo(req)
+	test.Equals(t, nil, err)
+	res := req.Result()
+	test.Equals(t, http.StatusForbidden, res.StatusCode)
+	e := &Envelope{}
+	json.Unmarshal(req.Body.Bytes(), e)
+	test.Equals(t, "forbidden", e.Message)
+}
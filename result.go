@@ -0,0 +1,91 @@
+package core
+
+import (
+	"os"
+	"time"
+)
+
+const (
+	// ExitCodeOK is the exit code for a service which was shut down gracefully.
+	ExitCodeOK = 0
+	// ExitCodeFailure is the exit code for a service which could not start or had a worker fail while running.
+	ExitCodeFailure = 1
+	// ExitCodeHaltFailure is the exit code for a service which had a worker fail to halt.
+	ExitCodeHaltFailure = 2
+	// ExitCodeTimeout is the exit code for a service which had workers still running after their halt deadline.
+	ExitCodeTimeout = 3
+)
+
+// WorkerResult represents how a single worker ended when run by the service.
+type WorkerResult struct {
+	// Name is the identity of the worker.
+	Name string
+	// Started reports whether the worker was started at all.
+	Started bool
+	// RunErr is the error returned by the worker when it stopped running.
+	RunErr error
+	// HaltErr is the error returned by the worker when it was told to halt.
+	HaltErr error
+	// Halted reports whether the worker was told to halt, as opposed to stopping on its own.
+	Halted bool
+	// HaltDuration is how long it took from the worker being told to halt until it stopped running.
+	HaltDuration time.Duration
+	// Timeout is the duration the worker was given to halt.
+	Timeout time.Duration
+	// Stopped reports whether the worker stopped running within its halt deadline.
+	Stopped bool
+}
+
+// RunResult represents how the service ended.
+type RunResult struct {
+	// Err is the error which prevented the service from starting at all.
+	Err error
+	// Signal is the signal which caused the service to shut down, if any.
+	Signal os.Signal
+	// Forced reports whether a second signal forced the service to exit before the workers had stopped.
+	Forced bool
+	// Workers are the results for each worker in the order they were given to the service.
+	Workers []WorkerResult
+}
+
+// Failed returns the results of workers which stopped running with an error.
+func (r RunResult) Failed() []WorkerResult {
+	var failed []WorkerResult
+	for _, w := range r.Workers {
+		if w.RunErr != nil {
+			failed = append(failed, w)
+		}
+	}
+	return failed
+}
+
+// TimedOut returns the results of workers which did not stop running within their halt deadline.
+func (r RunResult) TimedOut() []WorkerResult {
+	var timedOut []WorkerResult
+	for _, w := range r.Workers {
+		if !w.Stopped {
+			timedOut = append(timedOut, w)
+		}
+	}
+	return timedOut
+}
+
+// ExitCode maps the result to a process exit code.
+// A failure to start or a failing worker takes precedence over workers not stopping within their halt deadline,
+// which in turn takes precedence over workers failing to halt.
+func (r RunResult) ExitCode() int {
+	switch {
+	case r.Err != nil:
+		return ExitCodeFailure
+	case len(r.Failed()) > 0:
+		return ExitCodeFailure
+	case len(r.TimedOut()) > 0:
+		return ExitCodeTimeout
+	}
+	for _, w := range r.Workers {
+		if w.HaltErr != nil {
+			return ExitCodeHaltFailure
+		}
+	}
+	return ExitCodeOK
+}
@@ -0,0 +1,157 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+	"github.com/LUSHDigital/core/workers/httpsrv"
+)
+
+type funcs struct {
+	run  func(context.Context) error
+	halt func(context.Context) error
+}
+
+func (f *funcs) Run(ctx context.Context) error  { return f.run(ctx) }
+func (f *funcs) Halt(ctx context.Context) error { return f.halt(ctx) }
+
+func ExampleService_RunWithResult() {
+	service := core.NewService("example", "service")
+	res := service.RunWithResult(ctx,
+		httpsrv.NewDefault(handler),
+	)
+	for _, w := range res.Failed() {
+		fmt.Printf("%s failed: %v\n", w.Name, w.RunErr)
+	}
+	os.Exit(res.ExitCode())
+}
+
+func TestRunResult_ExitCode(t *testing.T) {
+	failure := fmt.Errorf("failure")
+	cases := []struct {
+		name     string
+		result   core.RunResult
+		expected int
+	}{
+		{
+			name: "clean shutdown on signal",
+			result: core.RunResult{
+				Signal:  syscall.SIGTERM,
+				Workers: []core.WorkerResult{{Started: true, Halted: true, Stopped: true}},
+			},
+			expected: core.ExitCodeOK,
+		},
+		{
+			name:     "failed to start",
+			result:   core.RunResult{Err: failure},
+			expected: core.ExitCodeFailure,
+		},
+		{
+			name: "worker failed",
+			result: core.RunResult{
+				Workers: []core.WorkerResult{
+					{Started: true, Stopped: true, RunErr: failure},
+					{Started: true, Halted: true, Stopped: false},
+				},
+			},
+			expected: core.ExitCodeFailure,
+		},
+		{
+			name: "worker timed out",
+			result: core.RunResult{
+				Signal: syscall.SIGTERM,
+				Workers: []core.WorkerResult{
+					{Started: true, Halted: true, Stopped: true, HaltErr: failure},
+					{Started: true, Halted: true, Stopped: false},
+				},
+			},
+			expected: core.ExitCodeTimeout,
+		},
+		{
+			name: "worker failed to halt",
+			result: core.RunResult{
+				Signal: syscall.SIGTERM,
+				Workers: []core.WorkerResult{
+					{Started: true, Halted: true, Stopped: true, HaltErr: failure},
+				},
+			},
+			expected: core.ExitCodeHaltFailure,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			test.Equals(t, c.expected, c.result.ExitCode())
+		})
+	}
+}
+
+func TestService_RunWithResult(t *testing.T) {
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: 50 * time.Millisecond}
+
+	t.Run("worker failed", func(t *testing.T) {
+		failure := fmt.Errorf("failure")
+		rec := &recorder{}
+		res := service.RunWithResult(context.Background(),
+			newWorker("server", rec),
+			&funcs{
+				run:  func(context.Context) error { return failure },
+				halt: func(context.Context) error { return nil },
+			},
+		)
+		test.Equals(t, core.ExitCodeFailure, res.ExitCode())
+		test.Equals(t, 1, len(res.Failed()))
+		test.Equals(t, "*core_test.funcs", res.Failed()[0].Name)
+		test.Equals(t, failure, res.Failed()[0].RunErr)
+		test.Equals(t, true, res.Workers[0].Halted)
+		test.Equals(t, true, res.Workers[0].Stopped)
+		test.Equals(t, false, res.Workers[1].Halted)
+	})
+
+	t.Run("worker timed out", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		release := make(chan struct{})
+		defer close(release)
+		res := service.RunWithResult(ctx,
+			&funcs{
+				run: func(context.Context) error {
+					<-release
+					return nil
+				},
+				halt: func(context.Context) error { return nil },
+			},
+		)
+		test.Equals(t, core.ExitCodeTimeout, res.ExitCode())
+		test.Equals(t, 1, len(res.TimedOut()))
+		test.Equals(t, true, res.Workers[0].HaltDuration >= 50*time.Millisecond)
+	})
+
+	t.Run("worker failed to halt", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		failure := fmt.Errorf("failure")
+		res := service.RunWithResult(ctx,
+			&funcs{
+				run: func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				},
+				halt: func(context.Context) error { return failure },
+			},
+		)
+		test.Equals(t, core.ExitCodeHaltFailure, res.ExitCode())
+		test.Equals(t, failure, res.Workers[0].HaltErr)
+	})
+
+	t.Run("invalid service", func(t *testing.T) {
+		res := (&core.Service{}).RunWithResult(context.Background(), newWorker("server", &recorder{}))
+		test.Equals(t, core.ExitCodeFailure, res.ExitCode())
+		test.Equals(t, core.ErrInvalidService{Problems: []string{"missing name", "missing type"}}, res.Err)
+	})
+}
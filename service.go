@@ -9,12 +9,9 @@ package core
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
-	"os/signal"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 )
 
@@ -37,8 +34,33 @@ type Service struct {
 	// Revision represents the SVC revision or commit hash of the service.
 	Revision string `json:"revision"`
 
-	// GracePeriod represents the duration workers have to clean up before the process gets killed.
+	// GracePeriod represents the duration each worker has to halt and clean up before the process gets killed,
+	// unless the worker provides its own halt timeout by implementing TimedHalter.
 	GracePeriod time.Duration `json:"grace_period"`
+	// DrainDelay represents the duration the service keeps running after it started draining, before halting any worker.
+	// It gives load balancers time to stop sending traffic once workers implementing Drainer report the service as going away.
+	DrainDelay time.Duration `json:"drain_delay"`
+
+	// Signals are the signals which shut down the service, defaulting to DefaultSignals when nil.
+	// Set it to an empty, non-nil slice to not listen for any shutdown signals.
+	Signals []os.Signal `json:"-"`
+	// ReloadSignals are the signals which reload workers implementing Reloader, defaulting to DefaultReloadSignals when nil.
+	// Set it to an empty, non-nil slice to not listen for any reload signals.
+	ReloadSignals []os.Signal `json:"-"`
+
+	// Logger is used for logging by the service and is passed on to every worker implementing Loggable.
+	// The default logger of the package is used when none is provided.
+	Logger Logger `json:"-"`
+
+	// PanicHandler is called with every panic recovered from running or halting a worker.
+	// Recovered panics are turned into an ErrPanic for the worker either way, shutting down the service like any other error.
+	PanicHandler PanicHandler `json:"-"`
+
+	// Observers are told about every lifecycle event of the service, together with any worker implementing Observer.
+	Observers []Observer `json:"-"`
+
+	mu    sync.Mutex
+	units []*unit
 }
 
 // NewService creates a new service based on
@@ -69,6 +91,12 @@ type Halter interface {
 	Halt(context.Context) error
 }
 
+// TimedHalter represents the behaviour for a worker which needs its own deadline for halting.
+type TimedHalter interface {
+	// HaltTimeout should return the duration the worker has to halt, instead of the grace period of the service.
+	HaltTimeout() time.Duration
+}
+
 // Worker represents the behaviour for a service worker.
 type Worker interface {
 	Runner
@@ -79,76 +107,143 @@ type Worker interface {
 // The process with an appropriate status code.
 // DEPRECATED: Use MustRun in favour of StartWorkers.
 func (s *Service) StartWorkers(ctx context.Context, workers ...Worker) {
-	log.Println("DEPRECATED: Use core.MustRun in favour of core.StartWorkers")
+	s.logger().Warn("DEPRECATED: Use core.MustRun in favour of core.StartWorkers")
 	s.MustRun(ctx, workers...)
 }
 
 // MustRun will start the given service workers and block block indefinitely, until interupted.
 // The process with an appropriate status code.
 func (s *Service) MustRun(ctx context.Context, workers ...Worker) {
-	os.Exit(s.Run(ctx, workers...))
+	os.Exit(s.RunWithResult(ctx, workers...).ExitCode())
 }
 
 // Run will start the given service workers and block block indefinitely, until interupted.
+// It returns the exit code mapped from the result of running the workers.
 func (s *Service) Run(ctx context.Context, workers ...Worker) int {
-	const fail int = 1
+	return s.RunWithResult(ctx, workers...).ExitCode()
+}
+
+// RunWithResult will start the given service workers and block block indefinitely, until interupted.
+// Workers are started in order of their dependencies, waiting for each stage of workers to be ready
+// before starting the next, and are halted in the reverse order.
+// The result reports how each of the workers ended.
+func (s *Service) RunWithResult(ctx context.Context, workers ...Worker) RunResult {
+	var (
+		res    RunResult
+		logger = s.logger()
+	)
 	nWorkers := len(workers)
 	if nWorkers < 1 {
-		log.Println("need at least 1 service worker")
-		return fail
+		res.Err = fmt.Errorf("need at least 1 service worker")
+		logger.Error("cannot start service", "error", res.Err)
+		return res
 	}
-	if err := s.validate(); err != nil {
-		log.Println(err)
-		return fail
+	if err := s.Validate(); err != nil {
+		res.Err = err
+		logger.Error("cannot start service", "error", res.Err)
+		return res
 	}
+
+	info := s.BuildInfo()
+	observe := notify(s.Name, s.observers(workers))
+	units := make([]*unit, nWorkers)
+	for i, worker := range workers {
+		if b, ok := unwrap(worker).(BuildInfoSetter); ok {
+			b.SetBuildInfo(info)
+		}
+		units[i] = newUnit(detached{ctx}, worker, logger)
+		units[i].onPanic = s.PanicHandler
+		units[i].observe = observe
+	}
+	s.mu.Lock()
+	s.units = units
+	s.mu.Unlock()
+
+	stages, err := stage(units)
+	if err != nil {
+		res.Err = err
+		logger.Error("cannot start service", "error", res.Err)
+		return res
+	}
+
 	var (
-		cancelled    <-chan int
-		completed    <-chan int
-		done, cancel func()
+		once     sync.Once
+		shutdown = make(chan struct{})
+		stop     = func() { once.Do(func() { close(shutdown) }) }
+		finished = make(chan struct{})
+		signals  = newSignalHandler(s.signals(), s.reloadSignals(), observe)
 	)
-	ctx, cancelled, cancel = ContextWithSignals(ctx)
-	completed, cancelled, done = WaitWithTimeout(nWorkers, cancelled, s.grace())
+	defer close(finished)
+	defer signals.stop()
+	go signals.handle(ctx, logger, shutdown, finished, stop, s.reload)
+
+	logger.Info(fmt.Sprintf("starting %s: %s", s.Type, s.name()))
+	observe(Event{Type: EventServiceStarting})
 
-	var run = func(ctx context.Context, worker Worker, done, cancel func()) {
-		if err := worker.Run(ctx); err != nil {
-			log.Println("service errored:", err)
-			go cancel()
+	var started [][]*unit
+start:
+	for _, stage := range stages {
+		for _, u := range stage {
+			u.start(stop)
 		}
-		done()
-	}
-	var halt = func(ctx context.Context, worker Worker) {
-		if err := worker.Halt(ctx); err != nil {
-			log.Println("service halted:", err)
+		started = append(started, stage)
+		for _, u := range stage {
+			if !u.waitReady(shutdown) {
+				break start
+			}
 		}
 	}
 
-	log.Printf("starting %s: %s", s.Type, s.name())
-
-	for _, worker := range workers {
-		go run(ctx, worker, done, cancel)
+	completed := make(chan struct{})
+	go func() {
+		for _, stage := range started {
+			for _, u := range stage {
+				<-u.done
+			}
+		}
+		close(completed)
+	}()
+	select {
+	case <-shutdown:
+		res.Forced = !s.drain(started, completed, signals.forced)
+	case <-completed:
 	}
-	for {
-		select {
-		case <-cancelled:
-			for _, worker := range workers {
-				go halt(ctx, worker)
+
+halt:
+	for i := len(started) - 1; i >= 0 && !res.Forced; i-- {
+		for _, u := range started[i] {
+			if !u.stopped() {
+				u.halt(s.grace())
 			}
-		case code := <-completed:
-			message := "shutdown gracefully..."
-			if code > 0 {
-				message = "failed to shutdown gracefully: killing!"
+		}
+		for _, u := range started[i] {
+			if !u.waitHalted(signals.forced) {
+				res.Forced = true
+				break halt
 			}
-			log.Println(message)
-			return code
 		}
 	}
-}
 
-func (s *Service) validate() error {
-	if s.Name == "" || s.Type == "" {
-		return fmt.Errorf("cannot start without a name or type")
+	res.Workers = make([]WorkerResult, nWorkers)
+	for i, u := range units {
+		res.Workers[i] = u.result()
+	}
+	select {
+	case res.Signal = <-signals.first:
+	default:
+	}
+
+	observe(Event{Type: EventServiceStopped})
+
+	switch code := res.ExitCode(); code {
+	case ExitCodeOK:
+		logger.Info("shutdown gracefully...")
+	case ExitCodeTimeout:
+		logger.Error("failed to shutdown gracefully: killing!", "exit_code", code)
+	default:
+		logger.Error("shutdown after failure...", "exit_code", code)
 	}
-	return nil
+	return res
 }
 
 func (s *Service) name() string {
@@ -166,6 +261,19 @@ func (s *Service) name() string {
 	return w.String()
 }
 
+// logger returns the logger of the service, adding the identity of the service to every record.
+func (s *Service) logger() Logger {
+	logger := s.Logger
+	if logger == nil {
+		logger = DefaultLogger()
+	}
+	return logger.With(
+		"service", s.Name,
+		"version", s.Version,
+		"revision", s.Revision,
+	)
+}
+
 func (s *Service) grace() time.Duration {
 	grace := s.GracePeriod
 	if grace == 0 {
@@ -175,49 +283,33 @@ func (s *Service) grace() time.Duration {
 }
 
 // WaitWithTimeout will wait for a number of pieces of work has finished and send a message on the completed channel.
+// Once cancelled, the code it was cancelled with is sent on the completed channel if the work has not finished
+// before the timeout.
 func WaitWithTimeout(delta int, cancelled <-chan int, timeout time.Duration) (<-chan int, <-chan int, func()) {
 	completedC := make(chan int, 1)
 	cancelledC := make(chan int, 1)
+	finished := make(chan struct{})
 	wg := &sync.WaitGroup{}
 	wg.Add(delta)
 	go func(wg *sync.WaitGroup) {
 		wg.Wait()
-		completedC <- 0
+		close(finished)
 	}(wg)
 	go func() {
 		select {
+		case <-finished:
+			completedC <- 0
 		case code := <-cancelled:
 			cancelledC <- code
-			time.Sleep(timeout)
-			completedC <- code
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			select {
+			case <-finished:
+				completedC <- 0
+			case <-timer.C:
+				completedC <- code
+			}
 		}
 	}()
 	return completedC, cancelledC, wg.Done
 }
-
-// ContextWithSignals creates a new instance of signal context.
-func ContextWithSignals(ctx context.Context) (context.Context, <-chan int, context.CancelFunc) {
-	var cancelCtx context.CancelFunc
-	ctx, cancelCtx = context.WithCancel(ctx)
-
-	sigs := make(chan os.Signal, 1)
-	cancelled := make(chan int, 1)
-
-	signal.Notify(sigs,
-		syscall.SIGINT,
-		syscall.SIGTERM,
-	)
-
-	var cancel = func() {
-		cancelCtx()
-		cancelled <- 1
-	}
-
-	go func() {
-		sig := <-sigs
-		log.Printf("received signal: %s", sig)
-		cancel()
-	}()
-
-	return ctx, cancelled, cancel
-}
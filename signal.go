@@ -0,0 +1,166 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var (
+	// DefaultSignals are the signals which shut down the service when none have been configured.
+	DefaultSignals = []os.Signal{
+		syscall.SIGINT,
+		syscall.SIGTERM,
+	}
+
+	// DefaultReloadSignals are the signals which reload the workers when none have been configured.
+	DefaultReloadSignals = []os.Signal{
+		syscall.SIGHUP,
+	}
+)
+
+// Reloader represents the behaviour for a worker which can reload its configuration without a restart.
+type Reloader interface {
+	// Reload should make the worker reload its configuration.
+	Reload(context.Context) error
+}
+
+// ContextWithSignals creates a new instance of signal context, which is cancelled when any of the signals is received.
+// When no signals are given it will listen for the default signals.
+// Calling the cancel function releases the signal registration and is safe to call more than once.
+func ContextWithSignals(ctx context.Context, sigs ...os.Signal) (context.Context, <-chan int, context.CancelFunc) {
+	if len(sigs) == 0 {
+		sigs = DefaultSignals
+	}
+	var cancelCtx context.CancelFunc
+	ctx, cancelCtx = context.WithCancel(ctx)
+
+	received := make(chan os.Signal, 1)
+	cancelled := make(chan int, 1)
+
+	signal.Notify(received, sigs...)
+
+	var once sync.Once
+	var cancel = func() {
+		once.Do(func() {
+			signal.Stop(received)
+			cancelCtx()
+			cancelled <- 1
+		})
+	}
+
+	go func() {
+		select {
+		case sig := <-received:
+			DefaultLogger().Info("received signal", "signal", sig)
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	return ctx, cancelled, cancel
+}
+
+// signals returns the signals which should shut down the service.
+func (s *Service) signals() []os.Signal {
+	if s.Signals == nil {
+		return DefaultSignals
+	}
+	return s.Signals
+}
+
+// reloadSignals returns the signals which should reload the workers.
+func (s *Service) reloadSignals() []os.Signal {
+	if s.ReloadSignals == nil {
+		return DefaultReloadSignals
+	}
+	return s.ReloadSignals
+}
+
+// reload reloads every running worker of the service implementing Reloader.
+func (s *Service) reload() {
+	s.mu.Lock()
+	units := s.units
+	s.mu.Unlock()
+	for _, u := range units {
+		u.reload()
+	}
+}
+
+// signalHandler dispatches the signals received while the service is running.
+type signalHandler struct {
+	shutdown []os.Signal
+	reload   []os.Signal
+	received chan os.Signal
+	// first receives the signal which initiated the shutdown.
+	first chan os.Signal
+	// forced is closed when a second shutdown signal is received.
+	forced  chan struct{}
+	observe func(Event)
+}
+
+func newSignalHandler(shutdown, reload []os.Signal, observe func(Event)) *signalHandler {
+	h := &signalHandler{
+		shutdown: shutdown,
+		reload:   reload,
+		observe:  observe,
+		received: make(chan os.Signal, 1),
+		first:    make(chan os.Signal, 1),
+		forced:   make(chan struct{}),
+	}
+	if sigs := append(append([]os.Signal{}, shutdown...), reload...); len(sigs) > 0 {
+		signal.Notify(h.received, sigs...)
+	}
+	return h
+}
+
+// stop releases the signal registration.
+func (h *signalHandler) stop() {
+	signal.Stop(h.received)
+}
+
+func (h *signalHandler) isReload(sig os.Signal) bool {
+	for _, r := range h.reload {
+		if r == sig {
+			return true
+		}
+	}
+	return false
+}
+
+// handle will dispatch signals until done is closed, calling stop on the first shutdown signal or when ctx is done,
+// and reload on every reload signal. A shutdown signal received once the service is shutting down will close the
+// forced channel.
+func (h *signalHandler) handle(ctx context.Context, logger Logger, shutdown, done <-chan struct{}, stop, reload func()) {
+	var stopping bool
+	ctxDone := ctx.Done()
+	for {
+		select {
+		case <-shutdown:
+			shutdown = nil
+			stopping = true
+		case sig := <-h.received:
+			h.observe(Event{Type: EventSignalReceived, Signal: sig})
+			switch {
+			case h.isReload(sig):
+				logger.Info("received signal: reloading...", "signal", sig)
+				reload()
+			case stopping:
+				logger.Warn("received second signal: forcing exit!", "signal", sig)
+				close(h.forced)
+				return
+			default:
+				logger.Info("received signal", "signal", sig)
+				h.first <- sig
+				stop()
+			}
+		case <-ctxDone:
+			ctxDone = nil
+			stop()
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package core_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+)
+
+type reloader struct {
+	funcs
+	reloaded chan struct{}
+}
+
+func (r *reloader) Reload(_ context.Context) error {
+	r.reloaded <- struct{}{}
+	return nil
+}
+
+func raise(t *testing.T, sig syscall.Signal) {
+	t.Helper()
+	if err := syscall.Kill(os.Getpid(), sig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestService_Run_signals(t *testing.T) {
+	started := make(chan struct{})
+	w := &reloader{
+		reloaded: make(chan struct{}, 1),
+		funcs: funcs{
+			run: func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				return nil
+			},
+			halt: func(context.Context) error { return nil },
+		},
+	}
+	service := &core.Service{
+		Name:    "test",
+		Type:    "service",
+		Signals: []os.Signal{syscall.SIGUSR1},
+	}
+
+	go func() {
+		<-started
+		raise(t, syscall.SIGHUP)
+		<-w.reloaded
+		raise(t, syscall.SIGUSR1)
+	}()
+
+	res := service.RunWithResult(context.Background(), w)
+	test.Equals(t, core.ExitCodeOK, res.ExitCode())
+	test.Equals(t, syscall.SIGUSR1, res.Signal)
+	test.Equals(t, false, res.Forced)
+}
+
+func TestService_Run_forcedBySecondSignal(t *testing.T) {
+	started := make(chan struct{})
+	halted := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	service := &core.Service{
+		Name:        "test",
+		Type:        "service",
+		GracePeriod: time.Minute,
+		Signals:     []os.Signal{syscall.SIGUSR1},
+	}
+
+	go func() {
+		<-started
+		raise(t, syscall.SIGUSR1)
+		<-halted
+		raise(t, syscall.SIGUSR1)
+	}()
+
+	begin := time.Now()
+	res := service.RunWithResult(context.Background(), &funcs{
+		run: func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		},
+		halt: func(context.Context) error {
+			close(halted)
+			return nil
+		},
+	})
+	test.Equals(t, true, time.Since(begin) < time.Minute)
+	test.Equals(t, true, res.Forced)
+	test.Equals(t, core.ExitCodeTimeout, res.ExitCode())
+}
+
+func TestContextWithSignals(t *testing.T) {
+	ctx, cancelled, cancel := core.ContextWithSignals(context.Background(), syscall.SIGUSR2)
+	raise(t, syscall.SIGUSR2)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled by signal")
+	}
+	test.Equals(t, 1, <-cancelled)
+	cancel()
+	cancel()
+}
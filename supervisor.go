@@ -0,0 +1,180 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Restart represents when a supervised worker should be restarted once it stops running.
+type Restart int
+
+const (
+	// RestartNever will never restart the worker, any error will shut down the service.
+	RestartNever Restart = iota
+	// RestartOnFailure will restart the worker whenever it stops running with an error.
+	RestartOnFailure
+	// RestartAlways will restart the worker whenever it stops running, unless the service is halting.
+	RestartAlways
+)
+
+func (r Restart) String() string {
+	switch r {
+	case RestartNever:
+		return "never"
+	case RestartOnFailure:
+		return "on-failure"
+	case RestartAlways:
+		return "always"
+	default:
+		return fmt.Sprintf("restart(%d)", int(r))
+	}
+}
+
+const (
+	// DefaultMinBackoff is the default delay before restarting a worker the first time.
+	DefaultMinBackoff = 100 * time.Millisecond
+	// DefaultMaxBackoff is the default upper limit for the delay before restarting a worker.
+	DefaultMaxBackoff = 30 * time.Second
+	// DefaultMaxRestarts is the default number of restarts allowed within the restart window.
+	DefaultMaxRestarts = 5
+	// DefaultRestartWindow is the default window of time in which restarts are counted.
+	DefaultRestartWindow = time.Minute
+)
+
+// RestartPolicy represents how a worker is supervised by the service when it stops running.
+// Any zero values will be replaced by their defaults.
+type RestartPolicy struct {
+	// Restart decides when the worker should be restarted.
+	Restart Restart
+	// MinBackoff is the delay before the first restart, doubling for every consecutive restart.
+	MinBackoff time.Duration
+	// MaxBackoff is the upper limit for the delay between restarts.
+	MaxBackoff time.Duration
+	// MaxRestarts is the number of restarts allowed within the window before escalating to a full shutdown.
+	// A negative number allows for unlimited restarts.
+	MaxRestarts int
+	// Window is the duration in which restarts are counted towards the maximum.
+	Window time.Duration
+}
+
+func (p RestartPolicy) withDefaults() RestartPolicy {
+	if p.MinBackoff <= 0 {
+		p.MinBackoff = DefaultMinBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	if p.MaxBackoff < p.MinBackoff {
+		p.MaxBackoff = p.MinBackoff
+	}
+	if p.MaxRestarts == 0 {
+		p.MaxRestarts = DefaultMaxRestarts
+	}
+	if p.Window <= 0 {
+		p.Window = DefaultRestartWindow
+	}
+	return p
+}
+
+// restart reports whether a worker which stopped with the given error should be restarted.
+func (p RestartPolicy) restart(err error) bool {
+	switch p.Restart {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+// Supervised represents the behaviour for a worker which provides its own restart policy.
+type Supervised interface {
+	// RestartPolicy should return how the worker is to be restarted when it stops running.
+	RestartPolicy() RestartPolicy
+}
+
+// Supervise wraps a worker and restarts it according to the policy when it is run by the service.
+func Supervise(worker Worker, policy RestartPolicy) Worker {
+	return &supervised{
+		Worker: worker,
+		policy: policy,
+	}
+}
+
+type supervised struct {
+	Worker
+	policy RestartPolicy
+}
+
+// RestartPolicy returns the restart policy of the worker.
+func (s *supervised) RestartPolicy() RestartPolicy {
+	return s.policy
+}
+
+func (s *supervised) unwrap() Worker {
+	return s.Worker
+}
+
+// restartPolicy finds the outermost restart policy declared by a worker or any of its wrappers.
+func restartPolicy(worker Worker) RestartPolicy {
+	for {
+		if s, ok := worker.(Supervised); ok {
+			return s.RestartPolicy().withDefaults()
+		}
+		w, ok := worker.(wrapper)
+		if !ok {
+			return RestartPolicy{}.withDefaults()
+		}
+		worker = w.unwrap()
+	}
+}
+
+// ErrRestartsExhausted represents an error for when a worker has used up its restarts.
+type ErrRestartsExhausted struct {
+	Worker   string
+	Restarts int
+	Window   time.Duration
+	Err      error
+}
+
+func (e ErrRestartsExhausted) Error() string {
+	return fmt.Sprintf("%s restarted %d times within %s: %v", e.Worker, e.Restarts, e.Window, e.Err)
+}
+
+// Unwrap returns the error the worker last stopped with.
+func (e ErrRestartsExhausted) Unwrap() error {
+	return e.Err
+}
+
+// backoff keeps track of restarts within the window of a restart policy.
+type backoff struct {
+	policy   RestartPolicy
+	restarts []time.Time
+	delay    time.Duration
+}
+
+// next returns the delay before the next restart, or false if the restarts have been used up.
+func (b *backoff) next(now time.Time) (time.Duration, bool) {
+	var recent []time.Time
+	for _, at := range b.restarts {
+		if now.Sub(at) < b.policy.Window {
+			recent = append(recent, at)
+		}
+	}
+	b.restarts = recent
+	if b.policy.MaxRestarts >= 0 && len(b.restarts) >= b.policy.MaxRestarts {
+		return 0, false
+	}
+	switch {
+	case len(b.restarts) == 0 || b.delay == 0:
+		b.delay = b.policy.MinBackoff
+	case b.delay < b.policy.MaxBackoff:
+		b.delay *= 2
+		if b.delay > b.policy.MaxBackoff {
+			b.delay = b.policy.MaxBackoff
+		}
+	}
+	b.restarts = append(b.restarts, now)
+	return b.delay, true
+}
@@ -0,0 +1,94 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+)
+
+type flaky struct {
+	failures int32
+	runs     int32
+}
+
+func (f *flaky) Run(ctx context.Context) error {
+	if n := atomic.AddInt32(&f.runs, 1); n <= f.failures {
+		return fmt.Errorf("flaky failure %d", n)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (f *flaky) Halt(_ context.Context) error {
+	return nil
+}
+
+func ExampleSupervise() {
+	consumer := &flaky{}
+	service := core.NewService("example", "service")
+	service.MustRun(ctx,
+		core.Supervise(consumer, core.RestartPolicy{
+			Restart:     core.RestartOnFailure,
+			MinBackoff:  time.Second,
+			MaxBackoff:  time.Minute,
+			MaxRestarts: 5,
+			Window:      10 * time.Minute,
+		}),
+	)
+}
+
+func TestService_Run_supervised(t *testing.T) {
+	consumer := &flaky{failures: 3}
+	rec := &recorder{}
+	server := newWorker("server", rec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for atomic.LoadInt32(&consumer.runs) < 4 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	code := service.Run(ctx,
+		server,
+		core.Supervise(core.DependsOn(consumer, server), core.RestartPolicy{
+			Restart:    core.RestartOnFailure,
+			MinBackoff: time.Millisecond,
+		}),
+	)
+	test.Equals(t, 0, code)
+	test.Equals(t, int32(4), atomic.LoadInt32(&consumer.runs))
+	test.Equals(t, []string{"server run", "server halt"}, rec.recorded())
+}
+
+func TestService_Run_supervisedEscalation(t *testing.T) {
+	consumer := &flaky{failures: 100}
+	rec := &recorder{}
+	server := newWorker("server", rec)
+
+	service := &core.Service{Name: "test", Type: "service", GracePeriod: time.Second}
+	service.Run(context.Background(),
+		server,
+		core.Supervise(core.DependsOn(consumer, server), core.RestartPolicy{
+			Restart:     core.RestartAlways,
+			MinBackoff:  time.Millisecond,
+			MaxRestarts: 2,
+		}),
+	)
+	test.Equals(t, int32(3), atomic.LoadInt32(&consumer.runs))
+	test.Equals(t, []string{"server run", "server halt"}, rec.recorded())
+}
+
+func TestRestartsExhausted(t *testing.T) {
+	cause := fmt.Errorf("cause")
+	err := core.ErrRestartsExhausted{Worker: "consumer", Restarts: 3, Window: time.Minute, Err: cause}
+	test.Equals(t, "consumer restarted 3 times within 1m0s: cause", err.Error())
+	test.Equals(t, cause, err.Unwrap())
+}
@@ -0,0 +1,298 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// readyInterval is how often a worker is checked for readiness while its dependants are waiting.
+	readyInterval = 50 * time.Millisecond
+)
+
+// checker represents the behaviour for a worker reporting whether it is ready, like a readysrv.Checker.
+type checker interface {
+	Check() ([]string, bool)
+}
+
+// unit keeps track of a single worker being run by the service.
+type unit struct {
+	name    string
+	log     Logger
+	wrapped Worker
+	worker  Worker
+	deps    []*unit
+	policy  RestartPolicy
+	onPanic PanicHandler
+	observe func(Event)
+	parent  context.Context
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	err     error
+
+	running   chan struct{}
+	stoppedAt time.Time
+	halting   bool
+	haltedAt  time.Time
+	timeout   time.Duration
+	missed    bool
+	haltDone  chan struct{}
+	haltErr   error
+
+	// mu guards the state read by Service.Workers while the worker is running.
+	mu        sync.Mutex
+	startedAt time.Time
+	lastErr   error
+}
+
+func newUnit(parent context.Context, worker Worker, logger Logger) *unit {
+	ctx, cancel := context.WithCancel(parent)
+	name := workerName(worker)
+	if l, ok := unwrap(worker).(Loggable); ok {
+		l.SetLogger(logger)
+	}
+	return &unit{
+		name:    name,
+		log:     logger.With("worker", name),
+		wrapped: worker,
+		worker:  unwrap(worker),
+		policy:  restartPolicy(worker),
+		parent:  parent,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+
+		observe:  func(Event) {},
+		running:  make(chan struct{}),
+		haltDone: make(chan struct{}),
+	}
+}
+
+// run will run the worker, restarting it according to its policy, and call fail if it cannot recover.
+func (u *unit) run(fail func()) {
+	defer close(u.done)
+	defer func() { u.stoppedAt = time.Now() }()
+	b := &backoff{policy: u.policy}
+	for {
+		u.mu.Lock()
+		u.startedAt = time.Now()
+		u.mu.Unlock()
+		u.observe(Event{Type: EventWorkerStarted, Worker: u.name})
+		err := u.protect(func() error { return u.worker.Run(u.ctx) })
+		if err != nil {
+			u.mu.Lock()
+			u.lastErr = err
+			u.mu.Unlock()
+			u.observe(Event{Type: EventWorkerErrored, Worker: u.name, Err: err})
+		}
+		u.observe(Event{Type: EventWorkerStopped, Worker: u.name, Err: err})
+		if u.ctx.Err() != nil {
+			if err != nil {
+				u.log.Error("service errored", "error", err)
+				u.err = err
+			}
+			return
+		}
+		if !u.policy.restart(err) {
+			if err != nil {
+				u.log.Error("service errored", "error", err)
+				u.err = err
+				fail()
+			}
+			return
+		}
+		delay, ok := b.next(time.Now())
+		if !ok {
+			u.err = ErrRestartsExhausted{
+				Worker:   u.name,
+				Restarts: len(b.restarts),
+				Window:   u.policy.Window,
+				Err:      err,
+			}
+			u.log.Error("service escalating to shutdown", "error", u.err)
+			fail()
+			return
+		}
+		u.log.Warn("service restarting", "delay", delay, "restart", u.policy.Restart, "error", err)
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-u.ctx.Done():
+			t.Stop()
+			return
+		}
+	}
+}
+
+// start will run the worker in the background.
+func (u *unit) start(fail func()) {
+	close(u.running)
+	go u.run(fail)
+}
+
+// halt will cancel the context of the worker and tell it to stop doing work, without waiting for it to stop.
+// The worker is given a fresh context for halting, with a deadline from its own halt timeout or the grace period.
+func (u *unit) halt(grace time.Duration) {
+	u.mu.Lock()
+	u.halting = true
+	u.mu.Unlock()
+	u.haltedAt = time.Now()
+	u.timeout = grace
+	if t, ok := u.worker.(TimedHalter); ok && t.HaltTimeout() > 0 {
+		u.timeout = t.HaltTimeout()
+	}
+	ctx, cancel := context.WithTimeout(u.parent, u.timeout)
+	go func() {
+		defer close(u.haltDone)
+		defer cancel()
+		u.cancel()
+		err := u.protect(func() error { return u.worker.Halt(ctx) })
+		if err != nil {
+			u.log.Error("service halted", "error", err)
+			u.haltErr = err
+		}
+		u.observe(Event{Type: EventWorkerHalted, Worker: u.name, Err: err})
+	}()
+}
+
+// stoppedAndHalted returns a channel which is closed once the worker has stopped running and any halt has returned.
+func (u *unit) stoppedAndHalted() <-chan struct{} {
+	if !u.halting {
+		return u.done
+	}
+	c := make(chan struct{})
+	go func() {
+		<-u.done
+		<-u.haltDone
+		close(c)
+	}()
+	return c
+}
+
+// waitHalted will block until the worker has stopped running and halted, or until its halt deadline has passed.
+// It returns false if exit is forced before either happens.
+func (u *unit) waitHalted(forced <-chan struct{}) bool {
+	if !u.halting {
+		return true
+	}
+	timer := time.NewTimer(time.Until(u.haltedAt.Add(u.timeout)))
+	defer timer.Stop()
+	select {
+	case <-u.stoppedAndHalted():
+	case <-timer.C:
+		u.missed = true
+		u.log.Error("service missed its halt deadline", "timeout", u.timeout)
+	case <-forced:
+		return false
+	}
+	return true
+}
+
+// result reports how the worker ended up to this point in time.
+func (u *unit) result() WorkerResult {
+	res := WorkerResult{
+		Name:    u.name,
+		Started: u.started(),
+		Halted:  u.halting,
+		Stopped: !u.started() || (u.stopped() && !u.missed),
+		Timeout: u.timeout,
+	}
+	if res.Started && res.Stopped {
+		res.RunErr = u.err
+	}
+	if u.halting {
+		if res.Stopped {
+			res.HaltDuration = u.stoppedAt.Sub(u.haltedAt)
+		} else {
+			res.HaltDuration = time.Since(u.haltedAt)
+		}
+		select {
+		case <-u.haltDone:
+			res.HaltErr = u.haltErr
+		default:
+		}
+	}
+	return res
+}
+
+// started reports whether the worker has been started.
+func (u *unit) started() bool {
+	select {
+	case <-u.running:
+		return true
+	default:
+		return false
+	}
+}
+
+// reload will tell a running worker to reload its configuration, if it is able to.
+func (u *unit) reload() {
+	r, ok := u.worker.(Reloader)
+	if !ok || !u.started() || u.stopped() {
+		return
+	}
+	if err := r.Reload(u.ctx); err != nil {
+		u.log.Error("service reload failed", "error", err)
+	}
+}
+
+// drain tells the worker the service is going away, if it is still running.
+func (u *unit) drain() {
+	d, ok := u.worker.(Drainer)
+	if !ok || !u.started() || u.stopped() {
+		return
+	}
+	d.Drain()
+}
+
+// stopped reports whether the worker has returned from running.
+func (u *unit) stopped() bool {
+	select {
+	case <-u.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// ready reports whether the worker is ready for its dependants to be started.
+func (u *unit) ready() bool {
+	if c, ok := u.worker.(checker); ok {
+		_, ok := c.Check()
+		return ok
+	}
+	return true
+}
+
+// waitReady will block until the worker is ready, has stopped or the service is shutting down.
+func (u *unit) waitReady(shutdown <-chan struct{}) bool {
+	ticker := time.NewTicker(readyInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-u.done:
+			return u.err == nil
+		default:
+		}
+		if u.ready() {
+			return true
+		}
+		select {
+		case <-u.done:
+		case <-shutdown:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// detached is a context which carries the values of its parent but is never cancelled along with it.
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detached) Done() <-chan struct{}       { return nil }
+func (detached) Err() error                  { return nil }
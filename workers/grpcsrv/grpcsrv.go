@@ -3,12 +3,14 @@ package grpcsrv
 
 import (
 	"context"
-	"log"
 	"net"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/LUSHDigital/core"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
@@ -36,21 +38,50 @@ func New(config *Config, options ...grpc.ServerOption) *Server {
 		}
 		config.Addr = addr
 	}
-	return &Server{
+	srv := &Server{
 		Connection: grpc.NewServer(options...),
+		Health:     health.NewServer(),
 		Now:        time.Now,
 		addr:       config.Addr,
-		addrC:      make(chan *net.TCPAddr, 1),
+		listening:  make(chan struct{}),
 	}
+	grpc_health_v1.RegisterHealthServer(srv.Connection, srv.Health)
+	return srv
 }
 
 // Server represents a collection of functions for starting and running an RPC server.
 type Server struct {
 	Connection *grpc.Server
+	Health     *health.Server
 	Now        func() time.Time
 	addr       string
-	addrC      chan *net.TCPAddr
+	addrMu     sync.Mutex
+	addrOnce   sync.Once
+	listening  chan struct{}
 	tcpAddr    *net.TCPAddr
+	logger     core.Logger
+}
+
+// Name returns the name of the worker.
+func (gs *Server) Name() string {
+	return "grpc server"
+}
+
+// ListenAddr returns the address the server is configured to listen on.
+func (gs *Server) ListenAddr() string {
+	return gs.addr
+}
+
+// SetLogger sets the logger used by the server.
+func (gs *Server) SetLogger(logger core.Logger) {
+	gs.logger = logger
+}
+
+func (gs *Server) log() core.Logger {
+	if gs.logger == nil {
+		return core.DefaultLogger()
+	}
+	return gs.logger
 }
 
 // Run will start the gRPC server and listen for requests.
@@ -59,33 +90,56 @@ func (gs *Server) Run(_ context.Context) error {
 	if err != nil {
 		return err
 	}
-	gs.addrC <- lis.Addr().(*net.TCPAddr)
-
-	hsrv := health.NewServer()
-	grpc_health_v1.RegisterHealthServer(gs.Connection, hsrv)
-
-	log.Printf("serving grpc on %s", gs.Addr().String())
+	gs.listened(lis.Addr().(*net.TCPAddr))
+	gs.log().Info("serving grpc", "addr", gs.Addr().String())
 	return gs.Connection.Serve(lis)
 }
 
-// Halt will attempt to gracefully shut down the server.
-func (gs *Server) Halt(_ context.Context) error {
-	log.Printf("stopping serving grpc on %s...", gs.Addr().String())
-	gs.Connection.GracefulStop()
-	return nil
+// Drain makes the health server report every service as not serving, while the server keeps handling requests.
+func (gs *Server) Drain() {
+	gs.log().Info("draining grpc", "addr", gs.Addr().String())
+	gs.Health.Shutdown()
+}
+
+// Halt will attempt to gracefully shut down the server, waiting for pending requests to finish.
+// When the context is done before they have, the server is stopped forcefully, closing every connection.
+func (gs *Server) Halt(ctx context.Context) error {
+	gs.log().Info("stopping serving grpc...", "addr", gs.Addr().String())
+	stopped := make(chan struct{})
+	go func() {
+		gs.Connection.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		gs.log().Warn("forcing grpc to stop", "addr", gs.Addr().String())
+		gs.Connection.Stop()
+		<-stopped
+		return ctx.Err()
+	}
 }
 
 // Addr will block until you have received an address for your server.
+// It is safe to call from multiple goroutines.
 func (gs *Server) Addr() *net.TCPAddr {
-	if gs.tcpAddr != nil {
-		return gs.tcpAddr
-	}
 	t := time.NewTimer(5 * time.Second)
+	defer t.Stop()
 	select {
-	case addr := <-gs.addrC:
-		gs.tcpAddr = addr
+	case <-gs.listening:
 	case <-t.C:
 		return &net.TCPAddr{}
 	}
+	gs.addrMu.Lock()
+	defer gs.addrMu.Unlock()
 	return gs.tcpAddr
 }
+
+// listened records the address the server is listening on, unblocking any calls to Addr.
+func (gs *Server) listened(addr *net.TCPAddr) {
+	gs.addrMu.Lock()
+	gs.tcpAddr = addr
+	gs.addrMu.Unlock()
+	gs.addrOnce.Do(func() { close(gs.listening) })
+}
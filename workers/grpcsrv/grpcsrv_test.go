@@ -57,6 +57,28 @@ func TestHealthCheck(t *testing.T) {
 	test.Equals(t, "SERVING", res.Status.String())
 }
 
+func TestServer_Drain(t *testing.T) {
+	server := grpcsrv.New(&grpcsrv.Config{
+		Addr: ":",
+	})
+	go server.Run(ctx)
+	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", server.Addr().Port), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	server.Drain()
+	client := grpc_health_v1.NewHealthClient(conn)
+	res, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Equals(t, "NOT_SERVING", res.Status.String())
+}
+
 func Example() {
 	srv := grpcsrv.New(&grpcsrv.Config{
 		Addr: ":8080",
@@ -82,3 +104,31 @@ func TestServer_Addr(t *testing.T) {
 	}
 
 }
+
+func TestServer_Halt(t *testing.T) {
+	server := grpcsrv.New(&grpcsrv.Config{
+		Addr: ":",
+	})
+	go server.Run(ctx)
+	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", server.Addr().Port), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// A watch stream stays open until the client or the server closes it, preventing a graceful stop.
+	client := grpc_health_v1.NewHealthClient(conn)
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	haltCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	test.Equals(t, context.DeadlineExceeded, server.Halt(haltCtx))
+	test.Equals(t, true, time.Since(start) < time.Second)
+}
@@ -4,17 +4,18 @@ package httpsrv
 import (
 	"context"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
 	"os"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dustin/go-humanize"
 
+	"github.com/LUSHDigital/core"
 	"github.com/LUSHDigital/core/rest"
 )
 
@@ -118,7 +119,7 @@ func CORSHandler(c CORS, next http.Handler) http.HandlerFunc {
 
 func handlePreflight(c CORS, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodOptions {
-		log.Printf("CORS pre-flight aborted: %s!=OPTIONS", r.Method)
+		core.DefaultLogger().Warn("CORS pre-flight aborted", "method", r.Method)
 		return
 	}
 	headers := w.Header()
@@ -160,10 +161,10 @@ func New(server *http.Server) *Server {
 		server.Addr = addr
 	}
 	return &Server{
-		Server: server,
-		Now:    time.Now,
-		addrC:  make(chan *net.TCPAddr, 1),
-		CORS:   DefaultCORS,
+		Server:    server,
+		Now:       time.Now,
+		listening: make(chan struct{}),
+		CORS:      DefaultCORS,
 	}
 }
 
@@ -176,11 +177,36 @@ type CORS struct {
 
 // Server represents a collection of functions for starting and running an RPC server.
 type Server struct {
-	Server  *http.Server
-	CORS    CORS
-	Now     func() time.Time
-	addrC   chan *net.TCPAddr
-	tcpAddr *net.TCPAddr
+	Server    *http.Server
+	CORS      CORS
+	Now       func() time.Time
+	addrMu    sync.Mutex
+	addrOnce  sync.Once
+	listening chan struct{}
+	tcpAddr   *net.TCPAddr
+	logger    core.Logger
+}
+
+// Name returns the name of the worker.
+func (gs *Server) Name() string {
+	return "http server"
+}
+
+// ListenAddr returns the address the server is configured to listen on.
+func (gs *Server) ListenAddr() string {
+	return gs.Server.Addr
+}
+
+// SetLogger sets the logger used by the server.
+func (gs *Server) SetLogger(logger core.Logger) {
+	gs.logger = logger
+}
+
+func (gs *Server) log() core.Logger {
+	if gs.logger == nil {
+		return core.DefaultLogger()
+	}
+	return gs.logger
 }
 
 // Run will start the gRPC server and listen for requests.
@@ -190,38 +216,49 @@ func (gs *Server) Run(_ context.Context) error {
 	if err != nil {
 		return err
 	}
-	gs.addrC <- lis.Addr().(*net.TCPAddr)
+	gs.listened(lis.Addr().(*net.TCPAddr))
 
 	if gs.Server.Handler == nil {
 		return fmt.Errorf("http server needs a handler")
 	}
 
 	gs.Server.Handler = WrapperHandler(gs.Now, gs.CORS, gs.Server.Handler)
-	log.Printf("serving http on http://%s", gs.Addr().String())
-	return gs.Server.Serve(lis)
+	gs.log().Info("serving http", "addr", "http://"+gs.Addr().String())
+	if err := gs.Server.Serve(lis); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
 }
 
 // Halt will attempt to gracefully shut down the server.
 func (gs *Server) Halt(ctx context.Context) error {
-	log.Printf("stopping serving http on http://%s...", gs.Addr().String())
+	gs.log().Info("stopping serving http...", "addr", "http://"+gs.Addr().String())
 	return gs.Server.Shutdown(ctx)
 }
 
 // Addr will block until you have received an address for your server.
+// It is safe to call from multiple goroutines.
 func (gs *Server) Addr() *net.TCPAddr {
-	if gs.tcpAddr != nil {
-		return gs.tcpAddr
-	}
 	t := time.NewTimer(5 * time.Second)
+	defer t.Stop()
 	select {
-	case addr := <-gs.addrC:
-		gs.tcpAddr = addr
+	case <-gs.listening:
 	case <-t.C:
 		return &net.TCPAddr{}
 	}
+	gs.addrMu.Lock()
+	defer gs.addrMu.Unlock()
 	return gs.tcpAddr
 }
 
+// listened records the address the server is listening on, unblocking any calls to Addr.
+func (gs *Server) listened(addr *net.TCPAddr) {
+	gs.addrMu.Lock()
+	gs.tcpAddr = addr
+	gs.addrMu.Unlock()
+	gs.addrOnce.Do(func() { close(gs.listening) })
+}
+
 // HealthResponse contains information about the service health.
 type HealthResponse struct {
 	Latency       string `json:"latency"`
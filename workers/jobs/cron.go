@@ -0,0 +1,317 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LUSHDigital/core"
+)
+
+// ErrInvalidSchedule is returned when a cron expression cannot be parsed.
+type ErrInvalidSchedule struct {
+	Spec   string
+	Reason string
+}
+
+func (e ErrInvalidSchedule) Error() string {
+	return fmt.Sprintf("invalid cron expression %q: %s", e.Spec, e.Reason)
+}
+
+// descriptors are the shorthands which can be used in place of the five fields of a cron expression.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// Schedule represents a parsed cron expression.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record whether the day fields were left unrestricted, which decides how they combine.
+	domAny, dowAny bool
+	location       *time.Location
+}
+
+// ParseCron parses a standard cron expression with five fields: minute, hour, day of month, month and day of week.
+// Fields support lists, ranges, steps and the names of months and days. The descriptors @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly can be used instead. The expression can be prefixed with
+// a time zone, eg. "CRON_TZ=Europe/London 0 9 * * MON-FRI".
+func ParseCron(spec string) (Schedule, error) {
+	var s Schedule
+	expr := strings.TrimSpace(spec)
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		parts := strings.SplitN(expr, " ", 2)
+		loc, err := time.LoadLocation(parts[0][strings.Index(parts[0], "=")+1:])
+		if err != nil {
+			return s, ErrInvalidSchedule{Spec: spec, Reason: err.Error()}
+		}
+		s.location = loc
+		expr = ""
+		if len(parts) == 2 {
+			expr = strings.TrimSpace(parts[1])
+		}
+	}
+	if d, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = d
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return s, ErrInvalidSchedule{Spec: spec, Reason: fmt.Sprintf("expected %d fields but got %d", len(fields), len(parts))}
+	}
+	bits := make([]uint64, len(fields))
+	for i, f := range fields {
+		b, err := f.parse(parts[i])
+		if err != nil {
+			return s, ErrInvalidSchedule{Spec: spec, Reason: err.Error()}
+		}
+		bits[i] = b
+	}
+	s.minute, s.hour, s.dom, s.month, s.dow = bits[0], bits[1], bits[2], bits[3], bits[4]
+	// Sunday can be written as both 0 and 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny = parts[2] == "*" || parts[2] == "?"
+	s.dowAny = parts[4] == "*" || parts[4] == "?"
+	return s, nil
+}
+
+func (f field) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q in %s", part[i+1:], f.name)
+			}
+			step = n
+			part = part[:i]
+		}
+		lo, hi := f.min, f.max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q in %s", part, f.name)
+			}
+		default:
+			v, err := f.value(part)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f field) value(s string) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s", s, f.name)
+	}
+	return v, nil
+}
+
+// Next returns the first time after t matching the schedule, or the zero time if there is none within five years.
+// The schedule is matched in its own time zone when it has one, or in the time zone of t otherwise.
+func (s Schedule) Next(t time.Time) time.Time {
+	loc := s.location
+	if loc == nil {
+		loc = t.Location()
+	}
+	t = t.In(loc)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay follows cron in matching either day field when both are restricted, and both otherwise.
+func (s Schedule) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if !s.domAny && !s.dowAny {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// CronConfig represents the configuration for running jobs on cron expressions.
+type CronConfig struct {
+	// Name identifies the worker in logs, events and results, defaulting to "cron".
+	Name string
+	// Location is the time zone for expressions without one of their own, defaulting to the local time zone.
+	Location *time.Location
+	// NoOverlap prevents a run of a job from starting while its previous run has not returned, skipping it instead.
+	NoOverlap bool
+}
+
+// NewCron creates a worker running jobs on cron expressions.
+func NewCron(config CronConfig) *Cron {
+	if config.Name == "" {
+		config.Name = "cron"
+	}
+	if config.Location == nil {
+		config.Location = time.Local
+	}
+	return &Cron{config: config}
+}
+
+// Cron represents a worker running jobs on cron expressions.
+type Cron struct {
+	config  CronConfig
+	entries []*cronEntry
+	logger  core.Logger
+}
+
+type cronEntry struct {
+	spec      string
+	scheduler *scheduler
+}
+
+// Add schedules the job to run on the cron expression, see ParseCron for the syntax.
+// Jobs should be added before the worker is run.
+func (c *Cron) Add(spec string, job Job) error {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	if schedule.location == nil {
+		schedule.location = c.config.Location
+	}
+	s := &scheduler{
+		job:       job,
+		noOverlap: c.config.NoOverlap,
+		now:       time.Now,
+	}
+	if c.logger != nil {
+		s.logger = c.logger.With("schedule", spec)
+	}
+	s.next = func(now time.Time) time.Duration {
+		next := schedule.Next(now)
+		if next.IsZero() {
+			// Never run a job whose schedule cannot be met, but keep waiting to be halted.
+			return 24 * time.Hour
+		}
+		return next.Sub(now)
+	}
+	c.entries = append(c.entries, &cronEntry{spec: spec, scheduler: s})
+	return nil
+}
+
+// Name returns the name of the worker.
+func (c *Cron) Name() string {
+	return c.config.Name
+}
+
+// SetLogger sets the logger used for reporting failing runs.
+func (c *Cron) SetLogger(logger core.Logger) {
+	c.logger = logger
+	for _, e := range c.entries {
+		e.scheduler.logger = logger.With("schedule", e.spec)
+	}
+}
+
+// Run will run every job on its schedule until halted.
+func (c *Cron) Run(ctx context.Context) error {
+	if len(c.entries) == 0 {
+		return fmt.Errorf("cron needs at least one job")
+	}
+	var wg sync.WaitGroup
+	for _, e := range c.entries {
+		wg.Add(1)
+		go func(s *scheduler) {
+			defer wg.Done()
+			s.run(ctx)
+		}(e.scheduler)
+	}
+	wg.Wait()
+	return nil
+}
+
+// Halt will stop running the jobs and wait for any current runs to return.
+// Every job is stopped before waiting, so a slow job does not keep the others running.
+func (c *Cron) Halt(ctx context.Context) error {
+	dones := make([]<-chan struct{}, len(c.entries))
+	for i, e := range c.entries {
+		dones[i] = e.scheduler.stop()
+	}
+	for _, done := range dones {
+		if err := wait(ctx, done); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check reports when each job last ran and fails if any of them returned an error.
+func (c *Cron) Check() ([]string, bool) {
+	var (
+		messages []string
+		ok       = true
+	)
+	for _, e := range c.entries {
+		msgs, eok := e.scheduler.status.Check()
+		for _, msg := range msgs {
+			messages = append(messages, e.spec+": "+msg)
+		}
+		ok = ok && eok
+	}
+	return messages, ok
+}
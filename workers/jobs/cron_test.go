@@ -0,0 +1,64 @@
+package jobs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core/test"
+	"github.com/LUSHDigital/core/workers/jobs"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip(err)
+	}
+	from := time.Date(2019, 10, 25, 10, 30, 15, 0, time.UTC) // Friday
+	cases := []struct {
+		spec     string
+		from     time.Time
+		expected time.Time
+	}{
+		{spec: "* * * * *", from: from, expected: time.Date(2019, 10, 25, 10, 31, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", from: from, expected: time.Date(2019, 10, 25, 10, 45, 0, 0, time.UTC)},
+		{spec: "0 9 * * MON-FRI", from: from, expected: time.Date(2019, 10, 28, 9, 0, 0, 0, time.UTC)},
+		{spec: "0 0 1 jan *", from: from, expected: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@daily", from: from, expected: time.Date(2019, 10, 26, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 12 * * 7", from: from, expected: time.Date(2019, 10, 27, 12, 0, 0, 0, time.UTC)},
+		{spec: "0 0 13 * 5", from: from, expected: time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", from: from, expected: time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "5,10-12 1 * * *", from: from, expected: time.Date(2019, 10, 26, 1, 5, 0, 0, time.UTC)},
+		{spec: "CRON_TZ=Europe/London 0 9 * * *", from: from, expected: time.Date(2019, 10, 26, 9, 0, 0, 0, london)},
+		{spec: "CRON_TZ=Europe/London 0 9 * * *", from: time.Date(2019, 10, 26, 9, 0, 0, 0, london), expected: time.Date(2019, 10, 27, 9, 0, 0, 0, london)},
+		{spec: "0 0 30 2 *", from: from, expected: time.Time{}},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			schedule, err := jobs.ParseCron(c.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.Equals(t, true, c.expected.Equal(schedule.Next(c.from)))
+		})
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	cases := []struct {
+		spec   string
+		reason string
+	}{
+		{spec: "", reason: "expected 5 fields but got 0"},
+		{spec: "60 * * * *", reason: `invalid value "60" in minute`},
+		{spec: "* * * foo *", reason: `invalid value "foo" in month`},
+		{spec: "5-1 * * * *", reason: `invalid range "5-1" in minute`},
+		{spec: "*/0 * * * *", reason: `invalid step "0" in minute`},
+		{spec: "CRON_TZ=Nowhere/Special * * * * *", reason: "unknown time zone Nowhere/Special"},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			_, err := jobs.ParseCron(c.spec)
+			test.Equals(t, jobs.ErrInvalidSchedule{Spec: c.spec, Reason: c.reason}, err)
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package jobs provides workers for running functions, either as a whole or on a schedule.
+package jobs
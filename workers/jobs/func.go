@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Job represents a function doing a single piece of work.
+type Job func(context.Context) error
+
+// NewFuncWorker creates a worker with a name, running and halting through the given functions.
+// The halt function is optional, the context passed to the run function is cancelled on halt either way.
+func NewFuncWorker(name string, run, halt func(context.Context) error) *FuncWorker {
+	if name == "" {
+		name = "func worker"
+	}
+	return &FuncWorker{
+		name: name,
+		run:  run,
+		halt: halt,
+	}
+}
+
+// FuncWorker represents a worker made up from a pair of functions.
+type FuncWorker struct {
+	name   string
+	run    func(context.Context) error
+	halt   func(context.Context) error
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	halted bool
+	status status
+}
+
+// Name returns the name of the worker.
+func (w *FuncWorker) Name() string {
+	return w.name
+}
+
+// Run will call the run function until it returns.
+func (w *FuncWorker) Run(ctx context.Context) error {
+	w.mu.Lock()
+	if w.halted {
+		w.mu.Unlock()
+		return nil
+	}
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.mu.Unlock()
+	defer w.cancel()
+
+	started := time.Now()
+	err := w.run(ctx)
+	w.status.record(started, err)
+	return err
+}
+
+// Halt will cancel the context of the run function and call the halt function.
+func (w *FuncWorker) Halt(ctx context.Context) error {
+	w.mu.Lock()
+	w.halted = true
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.mu.Unlock()
+	if w.halt == nil {
+		return nil
+	}
+	return w.halt(ctx)
+}
+
+// Check reports when the run function last returned and fails if it returned an error.
+func (w *FuncWorker) Check() ([]string, bool) {
+	return w.status.Check()
+}
@@ -0,0 +1,167 @@
+package jobs_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core"
+	"github.com/LUSHDigital/core/test"
+	"github.com/LUSHDigital/core/workers/jobs"
+	"github.com/LUSHDigital/core/workers/readysrv"
+)
+
+var (
+	ctx context.Context
+
+	_ readysrv.Checker = &jobs.FuncWorker{}
+	_ readysrv.Checker = &jobs.Periodic{}
+	_ readysrv.Checker = &jobs.Cron{}
+
+	_ core.Named = &jobs.FuncWorker{}
+	_ core.Named = &jobs.Periodic{}
+	_ core.Named = &jobs.Cron{}
+)
+
+func ExampleNewPeriodic() {
+	cleanup := jobs.NewPeriodic(jobs.PeriodicConfig{
+		Name:      "cleanup",
+		Interval:  time.Minute,
+		Jitter:    10 * time.Second,
+		NoOverlap: true,
+	}, func(ctx context.Context) error {
+		return nil
+	})
+	core.NewService("example", "service").MustRun(ctx, cleanup)
+}
+
+func ExampleNewCron() {
+	london, _ := time.LoadLocation("Europe/London")
+	cron := jobs.NewCron(jobs.CronConfig{Name: "reports", Location: london})
+	cron.Add("0 9 * * MON-FRI", func(ctx context.Context) error {
+		return nil
+	})
+	core.NewService("example", "service").MustRun(ctx, cron)
+}
+
+func TestFuncWorker(t *testing.T) {
+	failure := fmt.Errorf("failure")
+	w := jobs.NewFuncWorker("consumer", func(ctx context.Context) error {
+		<-ctx.Done()
+		return failure
+	}, nil)
+
+	messages, ok := w.Check()
+	test.Equals(t, true, ok)
+	test.Equals(t, []string{"job has not run yet"}, messages)
+
+	errs := make(chan error, 1)
+	go func() { errs <- w.Run(context.Background()) }()
+	time.Sleep(10 * time.Millisecond)
+	test.Equals(t, nil, w.Halt(context.Background()))
+	test.Equals(t, failure, <-errs)
+
+	_, ok = w.Check()
+	test.Equals(t, false, ok)
+}
+
+func TestName(t *testing.T) {
+	job := func(context.Context) error { return nil }
+	test.Equals(t, "consumer", jobs.NewFuncWorker("consumer", job, nil).Name())
+	test.Equals(t, "func worker", jobs.NewFuncWorker("", job, nil).Name())
+	test.Equals(t, "cleanup", jobs.NewPeriodic(jobs.PeriodicConfig{Name: "cleanup"}, job).Name())
+	test.Equals(t, "periodic job", jobs.NewPeriodic(jobs.PeriodicConfig{}, job).Name())
+	test.Equals(t, "reports", jobs.NewCron(jobs.CronConfig{Name: "reports"}).Name())
+	test.Equals(t, "cron", jobs.NewCron(jobs.CronConfig{}).Name())
+}
+
+func TestPeriodic(t *testing.T) {
+	var runs int32
+	p := jobs.NewPeriodic(jobs.PeriodicConfig{Interval: 5 * time.Millisecond}, func(context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	})
+	errs := make(chan error, 1)
+	go func() { errs <- p.Run(context.Background()) }()
+	for atomic.LoadInt32(&runs) < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	test.Equals(t, nil, p.Halt(context.Background()))
+	test.Equals(t, nil, <-errs)
+	_, ok := p.Check()
+	test.Equals(t, true, ok)
+}
+
+func TestPeriodic_noOverlap(t *testing.T) {
+	var (
+		runs    int32
+		running int32
+		overlap int32
+	)
+	p := jobs.NewPeriodic(jobs.PeriodicConfig{
+		Interval:  time.Millisecond,
+		Jitter:    time.Millisecond,
+		NoOverlap: true,
+	}, func(context.Context) error {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.StoreInt32(&overlap, 1)
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		atomic.AddInt32(&runs, 1)
+		return fmt.Errorf("failure")
+	})
+	p.SetLogger(core.NewStdLogger(nil))
+	go p.Run(context.Background())
+	for atomic.LoadInt32(&runs) < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	test.Equals(t, nil, p.Halt(context.Background()))
+	test.Equals(t, int32(0), atomic.LoadInt32(&overlap))
+	_, ok := p.Check()
+	test.Equals(t, false, ok)
+}
+
+func TestPeriodic_haltDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{}, 1)
+	p := jobs.NewPeriodic(jobs.PeriodicConfig{Interval: time.Millisecond, NoOverlap: true}, func(context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil
+	})
+	go p.Run(context.Background())
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	test.Equals(t, context.DeadlineExceeded, p.Halt(ctx))
+}
+
+func TestPeriodic_noInterval(t *testing.T) {
+	p := jobs.NewPeriodic(jobs.PeriodicConfig{}, func(context.Context) error { return nil })
+	test.Equals(t, fmt.Errorf("periodic job needs a positive interval"), p.Run(context.Background()))
+}
+
+func TestCron_Add(t *testing.T) {
+	cron := jobs.NewCron(jobs.CronConfig{})
+	test.Equals(t, nil, cron.Add("@hourly", func(context.Context) error { return nil }))
+	test.Equals(t, jobs.ErrInvalidSchedule{
+		Spec:   "* * *",
+		Reason: "expected 5 fields but got 3",
+	}, cron.Add("* * *", func(context.Context) error { return nil }))
+
+	errs := make(chan error, 1)
+	go func() { errs <- cron.Run(context.Background()) }()
+	test.Equals(t, nil, cron.Halt(context.Background()))
+	test.Equals(t, nil, <-errs)
+
+	messages, ok := cron.Check()
+	test.Equals(t, true, ok)
+	test.Equals(t, []string{"@hourly: job has not run yet"}, messages)
+}
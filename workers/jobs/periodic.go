@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/LUSHDigital/core"
+)
+
+// PeriodicConfig represents the configuration for running a job periodically.
+type PeriodicConfig struct {
+	// Name identifies the worker in logs, events and results, defaulting to "periodic job".
+	Name string
+	// Interval is the duration between the start of each run.
+	Interval time.Duration
+	// Jitter is the upper limit of a random duration added to every interval, to spread out runs across instances.
+	Jitter time.Duration
+	// NoOverlap prevents a run from starting while the previous run has not returned, skipping it instead.
+	NoOverlap bool
+}
+
+// NewPeriodic creates a worker running the job on a fixed interval.
+func NewPeriodic(config PeriodicConfig, job Job) *Periodic {
+	if config.Name == "" {
+		config.Name = "periodic job"
+	}
+	p := &Periodic{name: config.Name, interval: config.Interval}
+	p.scheduler = scheduler{
+		job:       job,
+		noOverlap: config.NoOverlap,
+		now:       time.Now,
+		next: func(time.Time) time.Duration {
+			if config.Jitter <= 0 {
+				return config.Interval
+			}
+			return config.Interval + time.Duration(rand.Int63n(int64(config.Jitter)))
+		},
+	}
+	return p
+}
+
+// Periodic represents a worker running a job on a fixed interval.
+type Periodic struct {
+	name      string
+	interval  time.Duration
+	scheduler scheduler
+}
+
+// Name returns the name of the worker.
+func (p *Periodic) Name() string {
+	return p.name
+}
+
+// SetLogger sets the logger used for reporting failing runs.
+func (p *Periodic) SetLogger(logger core.Logger) {
+	p.scheduler.logger = logger
+}
+
+// Run will run the job every interval until halted.
+func (p *Periodic) Run(ctx context.Context) error {
+	if p.interval <= 0 {
+		return fmt.Errorf("periodic job needs a positive interval")
+	}
+	return p.scheduler.run(ctx)
+}
+
+// Halt will stop running the job and wait for any current run to return.
+func (p *Periodic) Halt(ctx context.Context) error {
+	return p.scheduler.halt(ctx)
+}
+
+// Check reports when the job last ran and fails if it returned an error.
+func (p *Periodic) Check() ([]string, bool) {
+	return p.scheduler.status.Check()
+}
@@ -0,0 +1,111 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/LUSHDigital/core"
+)
+
+// scheduler runs a job every time the delay returned by next has passed, until it is halted.
+type scheduler struct {
+	job       Job
+	next      func(now time.Time) time.Duration
+	noOverlap bool
+	now       func() time.Time
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	halted  bool
+	done    chan struct{}
+	running int32
+	wg      sync.WaitGroup
+	status  status
+	logger  core.Logger
+}
+
+func (s *scheduler) log() core.Logger {
+	if s.logger == nil {
+		return core.DefaultLogger()
+	}
+	return s.logger
+}
+
+// run blocks until halted, waiting for every run of the job to return.
+func (s *scheduler) run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.halted {
+		s.mu.Unlock()
+		return nil
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	done := make(chan struct{})
+	s.done = done
+	s.mu.Unlock()
+	defer close(done)
+	defer s.cancel()
+	defer s.wg.Wait()
+
+	for {
+		timer := time.NewTimer(s.next(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick starts a run of the job, unless overlapping runs are prevented and the previous run has not returned.
+func (s *scheduler) tick(ctx context.Context) {
+	if s.noOverlap && !atomic.CompareAndSwapInt32(&s.running, 0, 1) {
+		s.log().Warn("skipping job run, previous run has not finished")
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if s.noOverlap {
+			defer atomic.StoreInt32(&s.running, 0)
+		}
+		started := s.now()
+		err := s.job(ctx)
+		if err != nil {
+			s.log().Error("job failed", "error", err)
+		}
+		s.status.record(started, err)
+	}()
+}
+
+// halt cancels the context of the running jobs and waits for them to return, or for the context to be done.
+func (s *scheduler) halt(ctx context.Context) error {
+	return wait(ctx, s.stop())
+}
+
+// stop cancels the context of the running jobs, returning a channel closed once they have returned.
+func (s *scheduler) stop() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.halted = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return s.done
+}
+
+// wait blocks until done is closed or the context is done, returning immediately for a nil channel.
+func wait(ctx context.Context, done <-chan struct{}) error {
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/core/test"
+)
+
+func TestCron_Halt_stopsEveryJob(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{}, 2)
+	cancelled := make(chan struct{})
+	once := func(now time.Time) time.Duration {
+		return time.Millisecond
+	}
+	stuck := &scheduler{now: time.Now, next: once, noOverlap: true, job: func(context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+	waiting := &scheduler{now: time.Now, next: once, noOverlap: true, job: func(ctx context.Context) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		close(cancelled)
+		return nil
+	}}
+	cron := &Cron{entries: []*cronEntry{
+		{spec: "stuck", scheduler: stuck},
+		{spec: "waiting", scheduler: waiting},
+	}}
+	go cron.Run(context.Background())
+	<-started
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	test.Equals(t, context.DeadlineExceeded, cron.Halt(ctx))
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("job was not cancelled after another job failed to halt")
+	}
+}
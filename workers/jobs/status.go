@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// status keeps track of the last run of a job, to report it as a readiness check.
+type status struct {
+	mu      sync.Mutex
+	lastRun time.Time
+	lastErr error
+}
+
+func (s *status) record(at time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRun = at
+	s.lastErr = err
+}
+
+// Check reports when the job last ran and fails if it returned an error.
+func (s *status) Check() ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.lastRun.IsZero():
+		return []string{"job has not run yet"}, true
+	case s.lastErr != nil:
+		return []string{fmt.Sprintf("job last ran at %s and failed: %v", s.lastRun.Format(time.RFC3339), s.lastErr)}, false
+	default:
+		return []string{fmt.Sprintf("job last ran at %s", s.lastRun.Format(time.RFC3339))}, true
+	}
+}
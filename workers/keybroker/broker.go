@@ -3,9 +3,10 @@ package keybroker
 import (
 	"context"
 	"fmt"
-	"log"
 	"sync"
 	"time"
+
+	"github.com/LUSHDigital/core"
 )
 
 // Renewer represents behaviour for marking a broker for renewal
@@ -50,9 +51,17 @@ type broker struct {
 	err       chan error
 	running   bool
 	keyType   string
+	logger    core.Logger
 	mu        sync.Mutex
 }
 
+func (b *broker) log() core.Logger {
+	if b.logger == nil {
+		return core.DefaultLogger()
+	}
+	return b.logger
+}
+
 func (b *broker) isRunning() bool {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -80,7 +89,7 @@ func (b *broker) Close() {
 
 // Run starts the broker.
 func (b *broker) Run(ctx context.Context) {
-	log.Printf("running %s broker checking for new key every %d second(s)\n", b.keyType, b.interval/time.Second)
+	b.log().Info(fmt.Sprintf("running %s broker", b.keyType), "interval", b.interval)
 	b.mu.Lock()
 	b.running = true
 	b.mu.Unlock()
@@ -93,23 +102,23 @@ func (b *broker) Run(ctx context.Context) {
 	for {
 		select {
 		case <-b.cancelled:
-			err := fmt.Errorf("%s broker cancelled", b.keyType)
-			log.Println(err)
-			b.err <- err
+			b.log().Info(fmt.Sprintf("%s broker cancelled", b.keyType))
+			b.err <- nil
 			return
 		case <-b.ticker.C:
 			select {
 			case <-b.renew:
 				bts, err := b.source.Get(ctx)
 				if err != nil {
-					log.Printf("%s broker interval error: %v\n", b.keyType, err)
+					b.log().Warn(fmt.Sprintf("%s broker interval error", b.keyType), "error", err)
 					b.Renew()
 				}
 				b.res <- bts
 			default:
 			}
 		case <-ctx.Done():
-			b.err <- fmt.Errorf("%s broker quit due to context timeout", b.keyType)
+			b.log().Info(fmt.Sprintf("%s broker quit due to context cancellation", b.keyType))
+			b.err <- nil
 			return
 		}
 	}
@@ -0,0 +1,108 @@
+package keybroker
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/LUSHDigital/core/auth"
+)
+
+const (
+	// DefaultJWKSRefreshInterval is the default minimum time between refreshing a key set for an unknown key ID.
+	DefaultJWKSRefreshInterval = time.Minute
+)
+
+// NewJWKSSource returns a source caching the JSON web key set retrieved from another source, eg. a HTTPSource.
+// When the source is nil, the JSON web key set is retrieved from the URL in the JWT_JWKS_URL environment variable.
+func NewJWKSSource(source Source) *JWKSSource {
+	if source == nil {
+		source = JWKSEnvHTTPSource
+	}
+	return &JWKSSource{
+		Source:          source,
+		RefreshInterval: DefaultJWKSRefreshInterval,
+		now:             time.Now,
+	}
+}
+
+// JWKSSource caches a JSON web key set, refreshing it when asked for a key ID it does not know.
+// It can be used as the key set of an auth.Parser, and as a source in its own right.
+type JWKSSource struct {
+	// Source is where the JSON web key set is retrieved from.
+	Source Source
+	// RefreshInterval is the minimum time between refreshing the key set when asked for an unknown key ID,
+	// so tokens with made up key IDs cannot cause the key set to be retrieved for every request.
+	RefreshInterval time.Duration
+
+	mu          sync.Mutex
+	raw         []byte
+	set         auth.JWKS
+	refreshedAt time.Time
+	now         func() time.Time
+}
+
+// Get returns the cached JSON web key set, retrieving it first if it has not yet been retrieved.
+func (s *JWKSSource) Get(ctx context.Context) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.raw == nil {
+		if err := s.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return s.raw, nil
+}
+
+// Refresh retrieves the JSON web key set from the source, replacing the cached key set.
+func (s *JWKSSource) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.refresh(ctx)
+}
+
+// PublicKey returns the public key with the key ID from the cached key set.
+// When the key ID is unknown, the key set is refreshed once the refresh interval has passed since it was last retrieved.
+func (s *JWKSSource) PublicKey(kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pk, err := s.set.PublicKey(kid)
+	if err != auth.ErrUnknownKeyID {
+		return pk, err
+	}
+	if !s.refreshedAt.IsZero() && s.now().Sub(s.refreshedAt) < s.RefreshInterval {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+	defer cancel()
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.set.PublicKey(kid)
+}
+
+// Check will see if the key set has been retrieved.
+func (s *JWKSSource) Check() ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.raw == nil {
+		return []string{"jwks source has not yet retrieved a key set"}, false
+	}
+	return []string{fmt.Sprintf("jwks source has retrieved %d keys", len(s.set.Keys))}, true
+}
+
+func (s *JWKSSource) refresh(ctx context.Context) error {
+	s.refreshedAt = s.now()
+	raw, err := s.Source.Get(ctx)
+	if err != nil {
+		return err
+	}
+	set, err := auth.ParseJWKS(raw)
+	if err != nil {
+		return fmt.Errorf("cannot parse jwks: %v", err)
+	}
+	s.raw, s.set = raw, set
+	return nil
+}
@@ -0,0 +1,101 @@
+package keybroker_test
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/LUSHDigital/core/auth"
+	"github.com/LUSHDigital/core/auth/authmock"
+	"github.com/LUSHDigital/core/test"
+	"github.com/LUSHDigital/core/workers/keybroker"
+)
+
+type jwksServer struct {
+	mu       sync.Mutex
+	keys     map[string]crypto.PublicKey
+	requests int
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	set, err := auth.NewJWKS(s.keys)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(set)
+}
+
+func (s *jwksServer) publish(kid string, key crypto.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[kid] = key
+}
+
+func (s *jwksServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func ExampleNewJWKSSource() {
+	keys := keybroker.NewJWKSSource(keybroker.HTTPSource("https://example.com/.well-known/jwks.json"))
+	parser := auth.NewKeySetParser(keys, auth.AllowAlgorithms("RS256"))
+	_ = parser
+}
+
+func TestJWKSSource_PublicKey(t *testing.T) {
+	_, one := authmock.MustNewRSAKeyPair()
+	_, two := authmock.MustNewEdDSAKeyPair()
+	jwks := &jwksServer{keys: map[string]crypto.PublicKey{"one": one}}
+	srv := httptest.NewServer(jwks)
+	defer srv.Close()
+
+	source := keybroker.NewJWKSSource(keybroker.HTTPSource(srv.URL))
+	_, ok := source.Check()
+	test.Equals(t, false, ok)
+
+	pk, err := source.PublicKey("one")
+	test.Equals(t, nil, err)
+	test.Equals(t, one, pk)
+	test.Equals(t, 1, jwks.count())
+	_, ok = source.Check()
+	test.Equals(t, true, ok)
+
+	t.Run("cached", func(t *testing.T) {
+		_, err := source.PublicKey("one")
+		test.Equals(t, nil, err)
+		test.Equals(t, 1, jwks.count())
+	})
+
+	t.Run("unknown key id within refresh interval", func(t *testing.T) {
+		_, err := source.PublicKey("unknown")
+		test.Equals(t, auth.ErrUnknownKeyID, err)
+		test.Equals(t, 1, jwks.count())
+	})
+
+	t.Run("refreshed for unknown key id", func(t *testing.T) {
+		jwks.publish("two", two)
+		source.RefreshInterval = 0
+		pk, err := source.PublicKey("two")
+		test.Equals(t, nil, err)
+		test.Equals(t, two, pk)
+		test.Equals(t, 2, jwks.count())
+	})
+
+	t.Run("get", func(t *testing.T) {
+		raw, err := source.Get(context.Background())
+		test.Equals(t, nil, err)
+		set, err := auth.ParseJWKS(raw)
+		test.Equals(t, nil, err)
+		test.Equals(t, 2, len(set.Keys))
+		test.Equals(t, 2, jwks.count())
+	})
+}
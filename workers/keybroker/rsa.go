@@ -4,10 +4,10 @@ import (
 	"context"
 	"crypto/rsa"
 	"fmt"
-	"log"
 	"math/big"
 	"sync"
 
+	"github.com/LUSHDigital/core"
 	"github.com/LUSHDigital/core/auth"
 
 	"github.com/dgrijalva/jwt-go"
@@ -42,7 +42,7 @@ var (
 // NewRSA returns a rsa public key broker based on configuration.
 // DEPRECATED: The function keybroker.NewRSA() has been deprecated in favour of keybroker.NewPublicRSA()
 func NewRSA(config *Config) *RSAPublicKeyBroker {
-	log.Println("DEPRECATED: The function keybroker.NewRSA() has been deprecated in favour of keybroker.NewPublicRSA()")
+	core.DefaultLogger().Warn("DEPRECATED: The function keybroker.NewRSA() has been deprecated in favour of keybroker.NewPublicRSA()")
 	return NewPublicRSA(config)
 }
 
@@ -95,11 +95,27 @@ func (b *RSAPublicKeyBroker) Copy() rsa.PublicKey {
 	return *b.key
 }
 
+// Name returns the name of the worker.
+func (b *RSAPublicKeyBroker) Name() string {
+	return b.broker.keyType + " broker"
+}
+
+// SetLogger sets the logger used by the broker.
+func (b *RSAPublicKeyBroker) SetLogger(logger core.Logger) {
+	b.broker.logger = logger
+}
+
 // Renew will inform the broker to force renewal of the key.
 func (b *RSAPublicKeyBroker) Renew() {
 	b.broker.Renew()
 }
 
+// Reload will inform the broker to force renewal of the key, for when the service is reloaded.
+func (b *RSAPublicKeyBroker) Reload(_ context.Context) error {
+	b.broker.Renew()
+	return nil
+}
+
 // Close stops the ticker and releases resources.
 func (b *RSAPublicKeyBroker) Close() {
 	b.broker.Close()
@@ -119,7 +135,7 @@ func (b *RSAPublicKeyBroker) Run(ctx context.Context) error {
 			if !ok {
 				return fmt.Errorf("key is not a valid rsa key: %T", key)
 			}
-			log.Printf("rsa public key broker found new key of size %d\n", key.Size())
+			b.broker.log().Info("rsa public key broker found new key", "size", key.Size())
 			b.mu.Lock()
 			b.key = key
 			b.mu.Unlock()
@@ -149,9 +165,33 @@ func (b *RSAPublicKeyBroker) Check() ([]string, bool) {
 
 // RSAPrivateKeyBroker defines the implementation for brokering an RSA public key
 type RSAPrivateKeyBroker struct {
-	broker *broker
-	key    *rsa.PrivateKey
-	mu     sync.Mutex
+	broker  *broker
+	key     *rsa.PrivateKey
+	issuers []*auth.RotatingIssuer
+	mu      sync.Mutex
+}
+
+// RotateIssuer makes the issuer rotate to every new key found by the broker, identified by its JWK thumbprint.
+// The issuer rotates to the current key of the broker straight away, if it has one.
+func (b *RSAPrivateKeyBroker) RotateIssuer(issuer *auth.RotatingIssuer) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.key != nil {
+		if err := rotate(issuer, b.key); err != nil {
+			return err
+		}
+	}
+	b.issuers = append(b.issuers, issuer)
+	return nil
+}
+
+func rotate(issuer *auth.RotatingIssuer, key *rsa.PrivateKey) error {
+	kid, err := auth.Thumbprint(key)
+	if err != nil {
+		return err
+	}
+	issuer.Rotate(kid, key, nil)
+	return nil
 }
 
 // Copy returns a shallow copy o the RSA private key.
@@ -164,11 +204,27 @@ func (b *RSAPrivateKeyBroker) Copy() rsa.PrivateKey {
 	return *b.key
 }
 
+// Name returns the name of the worker.
+func (b *RSAPrivateKeyBroker) Name() string {
+	return b.broker.keyType + " broker"
+}
+
+// SetLogger sets the logger used by the broker.
+func (b *RSAPrivateKeyBroker) SetLogger(logger core.Logger) {
+	b.broker.logger = logger
+}
+
 // Renew will inform the broker to force renewal of the key.
 func (b *RSAPrivateKeyBroker) Renew() {
 	b.broker.Renew()
 }
 
+// Reload will inform the broker to force renewal of the key, for when the service is reloaded.
+func (b *RSAPrivateKeyBroker) Reload(_ context.Context) error {
+	b.broker.Renew()
+	return nil
+}
+
 // Close stops the ticker and releases resources.
 func (b *RSAPrivateKeyBroker) Close() {
 	b.broker.Close()
@@ -184,9 +240,14 @@ func (b *RSAPrivateKeyBroker) Run(ctx context.Context) error {
 			if err != nil {
 				return fmt.Errorf("cannot parse rsa private key: %v", err)
 			}
-			log.Printf("rsa private key broker found new key of size %d\n", key.Size())
+			b.broker.log().Info("rsa private key broker found new key", "size", key.Size())
 			b.mu.Lock()
 			b.key = key
+			for _, issuer := range b.issuers {
+				if err := rotate(issuer, key); err != nil {
+					b.broker.log().Warn("rsa private key broker could not rotate issuer", "error", err)
+				}
+			}
 			b.mu.Unlock()
 		case err := <-b.broker.err:
 			return err
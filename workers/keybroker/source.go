@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"os"
 	"time"
+
+	"github.com/LUSHDigital/core"
 )
 
 // Source represents one or a chain of sources
@@ -24,10 +25,10 @@ func (sources Sources) Get(ctx context.Context) ([]byte, error) {
 	for _, source := range sources {
 		bts, err := source.Get(ctx)
 		if err == nil {
-			log.Printf("keybroker successfully resolved source %q\n", source)
+			core.DefaultLogger().Info("keybroker successfully resolved source", "source", fmt.Sprintf("%q", source))
 			return bts, nil
 		}
-		log.Printf("keybroker could not resolve source %q: %v: skipping...\n", source, err)
+		core.DefaultLogger().Warn("keybroker could not resolve source: skipping...", "source", fmt.Sprintf("%q", source), "error", err)
 	}
 	return nil, ErrNoSourcesResolved{
 		N: len(sources),
@@ -60,7 +61,7 @@ func (source HTTPSource) Get(ctx context.Context) ([]byte, error) {
 	}
 	defer func(c io.Closer) {
 		if err := c.Close(); err != nil {
-			log.Println(err)
+			core.DefaultLogger().Warn("keybroker could not close response body", "error", err)
 		}
 	}(res.Body)
 	if res.StatusCode != http.StatusOK {
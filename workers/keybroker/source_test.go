@@ -3,12 +3,14 @@ package keybroker_test
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
 	"path"
 	"testing"
 
+	"github.com/LUSHDigital/core/auth/authmock"
 	"github.com/LUSHDigital/core/test"
 	"github.com/LUSHDigital/core/workers/keybroker"
 )
@@ -32,22 +34,42 @@ var (
 	foobarStringSource = keybroker.StringSource("foobar")
 	barbazStringSource = keybroker.StringSource("barbaz")
 
-	onePubPath  = keybroker.FileSource(path.Join("testdata", "one.pub"))
-	onePubBytes = []byte(`-----BEGIN PUBLIC KEY-----
-MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQCckVt+i52W4M6XuPyd3u40SPql
-YbhRB9XiOBZJztokBc5SJet0i9OsakKKnLbZevsM3MPI+Oj4hwsqp9oLDrJ1LXJy
-IqI0OfMqq0f+YiPc0A6Uou1HiMDGSt7grwHkPVF7PDYeiNIAFR6e+rdTdWGLulx3
-eCLysKk3KiS+JZF/twIDAQAB
------END PUBLIC KEY-----`)
-	twoPubPath  = keybroker.FileSource(path.Join("testdata", "two.pub"))
-	twoPubBytes = []byte(`-----BEGIN PUBLIC KEY-----
-MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQDkeaMV5IrxxcoK6xpFaR6wBCTp
-1CZTOB3sCWuFG0YaGYo/4w4O2WVUUoYN4/dvZbHAyUAeeLT5+T4s6pLBebbzooU+
-pAU+iLlsgMQHCqm5s+yUYjniST15suYIbjhvP1o6VNHp5JgOoL+b/EHfZZZUP6LB
-Iy0Bo6vikx7871xzuwIDAQAB
------END PUBLIC KEY-----`)
+	fixtures    string
+	onePubPath  keybroker.FileSource
+	onePubBytes []byte
+	twoPubPath  keybroker.FileSource
+	twoPubBytes []byte
 )
 
+func TestMain(m *testing.M) {
+	var err error
+	if fixtures, err = ioutil.TempDir("", "keybroker"); err != nil {
+		panic(err)
+	}
+	for _, name := range []string{"one", "two"} {
+		private, _ := authmock.MustNewRSAKeyPair()
+		authmock.MustWriteKeyPair(private, path.Join(fixtures, name), path.Join(fixtures, name+".pub"))
+	}
+	onePubPath = keybroker.FileSource(path.Join(fixtures, "one.pub"))
+	onePubBytes = mustReadFile(string(onePubPath))
+	twoPubPath = keybroker.FileSource(path.Join(fixtures, "two.pub"))
+	twoPubBytes = mustReadFile(string(twoPubPath))
+	sourceString = string(onePubBytes)
+	privSourceString = string(mustReadFile(path.Join(fixtures, "one")))
+
+	code := m.Run()
+	os.RemoveAll(fixtures)
+	os.Exit(code)
+}
+
+func mustReadFile(path string) []byte {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
 func TestSources(t *testing.T) {
 	cases := []struct {
 		name          string
@@ -242,7 +264,7 @@ func TestHTTPSource(t *testing.T) {
 		panic(err)
 	}
 	port := l.Addr().(*net.TCPAddr).Port
-	fs := http.FileServer(http.Dir(path.Join("testdata")))
+	fs := http.FileServer(http.Dir(fixtures))
 	go http.Serve(l, fs)
 
 	cases := []struct {
@@ -279,7 +301,7 @@ func TestEnvHTTPSource(t *testing.T) {
 		panic(err)
 	}
 	port := l.Addr().(*net.TCPAddr).Port
-	fs := http.FileServer(http.Dir(path.Join("testdata")))
+	fs := http.FileServer(http.Dir(fixtures))
 	go http.Serve(l, fs)
 
 	os.Setenv("TEST_ONE", fmt.Sprintf("http://127.0.0.1:%d/one.pub", port))
@@ -21,6 +21,9 @@ var (
 		JWTPublicKeyDefaultFileSource,
 	}
 
+	// JWKSEnvHTTPSource represents the source of a JSON web key set at a HTTP GET destination
+	JWKSEnvHTTPSource = EnvHTTPSource("JWT_JWKS_URL")
+
 	// JWTPrivateKeyEnvStringSource represents the source of an RSA public key as a string
 	JWTPrivateKeyEnvStringSource = EnvStringSource("JWT_PRIVATE_KEY")
 
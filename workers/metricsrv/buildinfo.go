@@ -0,0 +1,50 @@
+package metricsrv
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/LUSHDigital/core"
+)
+
+// DefaultVersionPath is the path where we expose the build information by default.
+const DefaultVersionPath = "/version"
+
+// BuildInfoGauge is always 1 and labelled with the build information of the service.
+var BuildInfoGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "service_build_info",
+		Help: "Build information of the service, always 1",
+	},
+	[]string{"name", "type", "version", "revision", "go_version", "start_time"},
+)
+
+// SetBuildInfo exposes the build information of the service through the service_build_info metric and the version path.
+func (s *Server) SetBuildInfo(info core.BuildInfo) {
+	s.info = &info
+	BuildInfoGauge.WithLabelValues(
+		info.Name,
+		info.Type,
+		info.Version,
+		info.Revision,
+		info.GoVersion,
+		info.StartTime.UTC().Format(time.RFC3339),
+	).Set(1)
+}
+
+// VersionHandler provides a function for exposing the build information of a service over http as JSON.
+func VersionHandler(info core.BuildInfo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bts, err := json.Marshal(info)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.Write(bts)
+	}
+}
@@ -3,15 +3,17 @@ package metricsrv
 
 import (
 	"context"
-	"log"
 	"net"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/LUSHDigital/core"
 )
 
 const (
@@ -26,6 +28,8 @@ const (
 type Config struct {
 	Path   string
 	Server *http.Server
+	// Workers is used to expose the state of the workers of the service on the debug path, eg. service.Workers.
+	Workers func() []core.WorkerInfo
 }
 
 // New creates a new default metrics server.
@@ -49,42 +53,78 @@ func New(config *Config) *Server {
 		config.Server.Addr = DefaultAddr
 	}
 	return &Server{
-		Path:   path.Join("/", config.Path),
-		Server: config.Server,
-		addrC:  make(chan *net.TCPAddr, 1),
+		Path:      path.Join("/", config.Path),
+		Server:    config.Server,
+		Workers:   config.Workers,
+		listening: make(chan struct{}),
 	}
 }
 
 // Server represents a prometheus metrics server.
 type Server struct {
-	Path    string
-	Server  *http.Server
-	addrC   chan *net.TCPAddr
-	tcpAddr *net.TCPAddr
+	Path      string
+	Server    *http.Server
+	Workers   func() []core.WorkerInfo
+	addrMu    sync.Mutex
+	addrOnce  sync.Once
+	listening chan struct{}
+	tcpAddr   *net.TCPAddr
+	logger    core.Logger
+	info      *core.BuildInfo
+}
+
+// Name returns the name of the worker.
+func (s *Server) Name() string {
+	return "metrics server"
+}
+
+// ListenAddr returns the address the server is configured to listen on.
+func (s *Server) ListenAddr() string {
+	return s.Server.Addr
+}
+
+// SetLogger sets the logger used by the server.
+func (s *Server) SetLogger(logger core.Logger) {
+	s.logger = logger
+}
+
+func (s *Server) log() core.Logger {
+	if s.logger == nil {
+		return core.DefaultLogger()
+	}
+	return s.logger
 }
 
 // Addr will block until you have received an address for your server.
+// It is safe to call from multiple goroutines.
 func (s *Server) Addr() *net.TCPAddr {
-	if s.tcpAddr != nil {
-		return s.tcpAddr
-	}
 	t := time.NewTimer(5 * time.Second)
+	defer t.Stop()
 	select {
-	case addr := <-s.addrC:
-		s.tcpAddr = addr
+	case <-s.listening:
 	case <-t.C:
 		return &net.TCPAddr{}
 	}
+	s.addrMu.Lock()
+	defer s.addrMu.Unlock()
 	return s.tcpAddr
 }
 
+// listened records the address the server is listening on, unblocking any calls to Addr.
+func (s *Server) listened(addr *net.TCPAddr) {
+	s.addrMu.Lock()
+	s.tcpAddr = addr
+	s.addrMu.Unlock()
+	s.addrOnce.Do(func() { close(s.listening) })
+}
+
 // Run will start the metrics server.
 func (s *Server) Run(_ context.Context) error {
 	lis, err := net.Listen("tcp", s.Server.Addr)
 	if err != nil {
 		return err
 	}
-	s.addrC <- lis.Addr().(*net.TCPAddr)
+	s.listened(lis.Addr().(*net.TCPAddr))
 
 	mux := http.NewServeMux()
 	mux.Handle(s.Path, promhttp.Handler())
@@ -93,14 +133,23 @@ func (s *Server) Run(_ context.Context) error {
 	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	if s.Workers != nil {
+		mux.Handle(DefaultWorkersPath, WorkersHandler(s.Workers))
+	}
+	if s.info != nil {
+		mux.Handle(DefaultVersionPath, VersionHandler(*s.info))
+	}
 
 	s.Server.Handler = mux
-	log.Printf("serving profiling and prometheus metrics over http on http://%s%s", s.Addr().String(), s.Path)
-	return s.Server.Serve(lis)
+	s.log().Info("serving profiling and prometheus metrics over http", "addr", "http://"+s.Addr().String()+s.Path)
+	if err := s.Server.Serve(lis); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
 }
 
 // Halt will attempt to gracefully shut down the server.
 func (s *Server) Halt(ctx context.Context) error {
-	log.Printf("stopping serving profiling and prometheus metrics over http on http://%s...", s.Addr().String())
+	s.log().Info("stopping serving profiling and prometheus metrics over http...", "addr", "http://"+s.Addr().String())
 	return s.Server.Shutdown(ctx)
 }
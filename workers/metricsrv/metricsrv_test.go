@@ -2,10 +2,16 @@ package metricsrv_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/LUSHDigital/core"
 	"github.com/LUSHDigital/core/test"
 
 	"github.com/LUSHDigital/core/workers/metricsrv"
@@ -61,3 +67,64 @@ func TestServer_Addr(t *testing.T) {
 	}
 
 }
+
+func TestServer_SetBuildInfo(t *testing.T) {
+	info := core.BuildInfo{
+		Name:      "test",
+		Type:      "service",
+		Version:   "1.0.0",
+		Revision:  "abcdef",
+		GoVersion: "go1.14",
+		StartTime: time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+	srv := metricsrv.New(nil)
+	srv.SetBuildInfo(info)
+	gauge := metricsrv.BuildInfoGauge.WithLabelValues("test", "service", "1.0.0", "abcdef", "go1.14", "2019-10-01T12:00:00Z")
+	test.Equals(t, float64(1), testutil.ToFloat64(gauge))
+}
+
+func TestVersionHandler(t *testing.T) {
+	info := core.BuildInfo{
+		Name:      "test",
+		Type:      "service",
+		Version:   "1.0.0",
+		Revision:  "abcdef",
+		GoVersion: "go1.14",
+		StartTime: time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+	rr := httptest.NewRecorder()
+	metricsrv.VersionHandler(info).ServeHTTP(rr, httptest.NewRequest("GET", "/version", nil))
+	test.Equals(t, http.StatusOK, rr.Code)
+	test.Equals(t, "application/json", rr.Header().Get("Content-Type"))
+	test.Equals(t, `{"name":"test","type":"service","version":"1.0.0","revision":"abcdef","go_version":"go1.14","start_time":"2019-10-01T12:00:00Z"}`, rr.Body.String())
+}
+
+func TestServer_Observe(t *testing.T) {
+	srv := metricsrv.New(nil)
+	gauge := metricsrv.LiveWorkersGauge.WithLabelValues("test", "consumer")
+	srv.Observe(core.Event{Type: core.EventWorkerStarted, Service: "test", Worker: "consumer"})
+	test.Equals(t, float64(1), testutil.ToFloat64(gauge))
+	srv.Observe(core.Event{Type: core.EventWorkerStopped, Service: "test", Worker: "consumer"})
+	test.Equals(t, float64(0), testutil.ToFloat64(gauge))
+}
+
+func TestWorkersHandler(t *testing.T) {
+	srv := metricsrv.New(&metricsrv.Config{
+		Workers: func() []core.WorkerInfo {
+			return []core.WorkerInfo{
+				{Name: "http server", State: core.WorkerRunning, StartedAt: time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)},
+				{Name: "consumer", State: core.WorkerFailed, StartedAt: time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC), Err: fmt.Errorf("failure")},
+				{Name: "cron", State: core.WorkerPending},
+			}
+		},
+	})
+	test.Equals(t, "metrics server", srv.Name())
+	rr := httptest.NewRecorder()
+	metricsrv.WorkersHandler(srv.Workers).ServeHTTP(rr, httptest.NewRequest("GET", metricsrv.DefaultWorkersPath, nil))
+	test.Equals(t, http.StatusOK, rr.Code)
+	test.Equals(t, `[`+
+		`{"name":"http server","state":"running","started_at":"2019-10-01T12:00:00Z"},`+
+		`{"name":"consumer","state":"failed","started_at":"2019-10-01T12:00:00Z","error":"failure"},`+
+		`{"name":"cron","state":"pending"}`+
+		`]`, rr.Body.String())
+}
@@ -0,0 +1,27 @@
+package metricsrv
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/LUSHDigital/core"
+)
+
+// LiveWorkersGauge is 1 for every worker of a service while it is running, and 0 once it has stopped.
+var LiveWorkersGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "service_workers_live",
+		Help: "Whether each worker of the service is running",
+	},
+	[]string{"service", "worker"},
+)
+
+// Observe keeps the live workers gauge up to date with the lifecycle events of the service.
+func (s *Server) Observe(e core.Event) {
+	switch e.Type {
+	case core.EventWorkerStarted:
+		LiveWorkersGauge.WithLabelValues(e.Service, e.Worker).Set(1)
+	case core.EventWorkerStopped:
+		LiveWorkersGauge.WithLabelValues(e.Service, e.Worker).Set(0)
+	}
+}
@@ -0,0 +1,46 @@
+package metricsrv
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/LUSHDigital/core"
+)
+
+// DefaultWorkersPath is the path where we expose the state of the workers by default.
+const DefaultWorkersPath = "/debug/workers"
+
+// WorkersHandler provides a function for exposing the state of the workers of a service over http as JSON.
+func WorkersHandler(workers func() []core.WorkerInfo) http.HandlerFunc {
+	type worker struct {
+		Name      string           `json:"name"`
+		State     core.WorkerState `json:"state"`
+		StartedAt *time.Time       `json:"started_at,omitempty"`
+		Error     string           `json:"error,omitempty"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		infos := workers()
+		res := make([]worker, len(infos))
+		for i, info := range infos {
+			res[i] = worker{
+				Name:  info.Name,
+				State: info.State,
+			}
+			if !info.StartedAt.IsZero() {
+				startedAt := info.StartedAt
+				res[i].StartedAt = &startedAt
+			}
+			if info.Err != nil {
+				res[i].Error = info.Err.Error()
+			}
+		}
+		bts, err := json.Marshal(res)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.Write(bts)
+	}
+}
@@ -0,0 +1,58 @@
+package readysrv
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/LUSHDigital/core"
+)
+
+// workers keeps track of the workers of the service which stopped running with an error.
+type workers struct {
+	mu       sync.Mutex
+	observed bool
+	errored  map[string]error
+}
+
+func (w *workers) observe(e core.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.observed = true
+	switch e.Type {
+	case core.EventWorkerStarted:
+		delete(w.errored, e.Worker)
+	case core.EventWorkerErrored:
+		if w.errored == nil {
+			w.errored = make(map[string]error)
+		}
+		w.errored[e.Worker] = e.Err
+	}
+}
+
+// checks returns a check of the workers once any lifecycle event has been observed.
+func (w *workers) checks() Checks {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.observed {
+		return nil
+	}
+	return Checks{"workers": CheckerFunc(w.check)}
+}
+
+func (w *workers) check() ([]string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	messages := []string{}
+	for name, err := range w.errored {
+		messages = append(messages, fmt.Sprintf("%s errored: %v", name, err))
+	}
+	sort.Strings(messages)
+	return messages, len(messages) == 0
+}
+
+// Observe makes the server report the service as not ready while any of its workers has stopped with an error
+// and has not been restarted.
+func (s *Server) Observe(e core.Event) {
+	s.workers.observe(e)
+}
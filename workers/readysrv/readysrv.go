@@ -4,12 +4,15 @@ package readysrv
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"net"
 	"net/http"
 	"os"
 	"path"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/LUSHDigital/core"
 )
 
 const (
@@ -46,76 +49,138 @@ func New(config *Config, checks Checks) *Server {
 	if config.Server.Addr == "" {
 		config.Server.Addr = DefaultAddr
 	}
-	config.Server.Handler = CheckHandler(checks)
-	return &Server{
-		Checks: checks,
-		Server: config.Server,
-		Path:   path.Join("/", config.Path),
-		addrC:  make(chan *net.TCPAddr, 1),
+	srv := &Server{
+		Checks:    checks,
+		Server:    config.Server,
+		Path:      path.Join("/", config.Path),
+		listening: make(chan struct{}),
 	}
+	config.Server.Handler = checkHandler(srv.log, srv.isDraining, srv.workers.checks, checks)
+	return srv
 }
 
 // Server defines a readiness server.
 type Server struct {
-	Path    string
-	Checks  Checks
-	Server  *http.Server
-	addrC   chan *net.TCPAddr
-	tcpAddr *net.TCPAddr
+	Path      string
+	Checks    Checks
+	Server    *http.Server
+	addrMu    sync.Mutex
+	addrOnce  sync.Once
+	listening chan struct{}
+	tcpAddr   *net.TCPAddr
+	logger    core.Logger
+	drained   int32
+	workers   workers
+}
+
+// Name returns the name of the worker.
+func (s *Server) Name() string {
+	return "readiness server"
+}
+
+// ListenAddr returns the address the server is configured to listen on.
+func (s *Server) ListenAddr() string {
+	return s.Server.Addr
+}
+
+// SetLogger sets the logger used by the server.
+func (s *Server) SetLogger(logger core.Logger) {
+	s.logger = logger
+}
+
+func (s *Server) log() core.Logger {
+	if s.logger == nil {
+		return core.DefaultLogger()
+	}
+	return s.logger
+}
+
+// Drain makes the server report the service as not ready, regardless of the checks, until it is halted.
+func (s *Server) Drain() {
+	atomic.StoreInt32(&s.drained, 1)
+}
+
+func (s *Server) isDraining() bool {
+	return atomic.LoadInt32(&s.drained) == 1
 }
 
 // Addr will block until you have received an address for your server.
+// It is safe to call from multiple goroutines.
 func (s *Server) Addr() *net.TCPAddr {
-	if s.tcpAddr != nil {
-		return s.tcpAddr
-	}
 	t := time.NewTimer(5 * time.Second)
+	defer t.Stop()
 	select {
-	case addr := <-s.addrC:
-		s.tcpAddr = addr
+	case <-s.listening:
 	case <-t.C:
 		return &net.TCPAddr{}
 	}
+	s.addrMu.Lock()
+	defer s.addrMu.Unlock()
 	return s.tcpAddr
 }
 
+// listened records the address the server is listening on, unblocking any calls to Addr.
+func (s *Server) listened(addr *net.TCPAddr) {
+	s.addrMu.Lock()
+	s.tcpAddr = addr
+	s.addrMu.Unlock()
+	s.addrOnce.Do(func() { close(s.listening) })
+}
+
 // Run will start the ready server.
 func (s *Server) Run(_ context.Context) error {
 	lis, err := net.Listen("tcp", s.Server.Addr)
 	if err != nil {
 		return err
 	}
-	s.addrC <- lis.Addr().(*net.TCPAddr)
-	log.Printf("serving readiness checks server over http on http://%s%s", s.Addr(), s.Path)
-	return s.Server.Serve(lis)
+	s.listened(lis.Addr().(*net.TCPAddr))
+	s.log().Info("serving readiness checks server over http", "addr", "http://"+s.Addr().String()+s.Path)
+	if err := s.Server.Serve(lis); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
 }
 
 // Halt will attempt to gracefully shut down the server.
 func (s *Server) Halt(ctx context.Context) error {
-	log.Printf("stopping readiness checks server over http on http://%s...", s.Addr().String())
+	s.log().Info("stopping readiness checks server over http...", "addr", "http://"+s.Addr().String())
 	return s.Server.Shutdown(ctx)
 }
 
 // CheckHandler provides a function for providing health checks over http.
 func CheckHandler(checks Checks) http.HandlerFunc {
+	return checkHandler(core.DefaultLogger, func() bool { return false }, func() Checks { return nil }, checks)
+}
+
+func checkHandler(logger func() core.Logger, draining func() bool, extra func() Checks, checks Checks) http.HandlerFunc {
 	type health struct {
 		OK       bool     `json:"ok"`
 		Messages []string `json:"messages"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
+		if draining() {
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]health{
+				"service": {OK: false, Messages: []string{"draining"}},
+			})
+			return
+		}
 		var ready = true
 		res := make(map[string]health)
-		for name, check := range checks {
-			messages, ok := check.Check()
-			if !ok {
-				ready = false
-				for _, msg := range messages {
-					log.Printf("readysrv: %s: %s\n", name, msg)
+		for _, checks := range []Checks{checks, extra()} {
+			for name, check := range checks {
+				messages, ok := check.Check()
+				if !ok {
+					ready = false
+					for _, msg := range messages {
+						logger().Warn("readysrv: check failed", "check", name, "message", msg)
+					}
+				}
+				res[name] = health{
+					OK:       ok,
+					Messages: messages,
 				}
-			}
-			res[name] = health{
-				OK:       ok,
-				Messages: messages,
 			}
 		}
 
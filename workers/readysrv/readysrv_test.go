@@ -2,10 +2,13 @@ package readysrv_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/LUSHDigital/core"
 	"github.com/LUSHDigital/core/test"
 	"github.com/LUSHDigital/core/workers/readysrv"
 )
@@ -76,3 +79,41 @@ func TestCheckerFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_Drain(t *testing.T) {
+	srv := readysrv.New(nil, readysrv.Checks{
+		"a": readysrv.CheckerFunc(func() ([]string, bool) { return []string{}, true }),
+	})
+	check := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.Server.Handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+		return rr
+	}
+	test.Equals(t, http.StatusOK, check().Code)
+
+	srv.Drain()
+	rr := check()
+	test.Equals(t, http.StatusServiceUnavailable, rr.Code)
+	test.Equals(t, `{"service":{"ok":false,"messages":["draining"]}}`, strings.TrimSpace(rr.Body.String()))
+}
+
+func TestServer_Observe(t *testing.T) {
+	srv := readysrv.New(nil, readysrv.Checks{})
+	check := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.Server.Handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+		return rr
+	}
+	test.Equals(t, `{}`, check().Body.String())
+
+	srv.Observe(core.Event{Type: core.EventWorkerStarted, Worker: "consumer"})
+	test.Equals(t, `{"workers":{"ok":true,"messages":[]}}`, check().Body.String())
+
+	srv.Observe(core.Event{Type: core.EventWorkerErrored, Worker: "consumer", Err: fmt.Errorf("failure")})
+	rr := check()
+	test.Equals(t, http.StatusInternalServerError, rr.Code)
+	test.Equals(t, `{"workers":{"ok":false,"messages":["consumer errored: failure"]}}`, rr.Body.String())
+
+	srv.Observe(core.Event{Type: core.EventWorkerStarted, Worker: "consumer"})
+	test.Equals(t, http.StatusOK, check().Code)
+}